@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fitzix/go-localize/localize"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultLocaleName = localize.DefaultLocale
+	defaultOutputDir  = localize.DefaultOutput
+)
+
+var (
+	errFlagInputNotSet         = errors.New("the flag -input must be set")
+	errFlagEmitInvalid         = errors.New("the flag -emit must be a comma-separated list of generator names, each optionally followed by =<dir>")
+	errFlagCSVDelimiterInvalid = errors.New("the flag -csv-delimiter must be a single character other than a quote or a newline, or \"\\t\"")
+)
+
+// config holds the settings of a run. It is read from the YAML (or JSON) file
+// given with -config, and any flag set on the command line overrides it.
+type config struct {
+	Input  string `yaml:"input"`
+	Output string `yaml:"output"`
+	// Emit lists the generators to run, e.g. "go,json=web/locales".
+	Emit           string `yaml:"emit"`
+	Runtime        string `yaml:"runtime"`
+	DefaultLocale  string `yaml:"default_locale"`
+	FallbackLocale string `yaml:"fallback_locale"`
+	Timestamp      bool   `yaml:"timestamp"`
+	// ReportMissing reports the keys each locale is missing compared to the
+	// default locale, failing the run when Strict is set.
+	ReportMissing bool   `yaml:"report_missing"`
+	ReportFormat  string `yaml:"report_format"`
+	Strict        bool   `yaml:"strict"`
+	Duplicates    string `yaml:"duplicates"`
+	Aliases       string `yaml:"aliases"`
+	KeyCollisions string `yaml:"key_collisions"`
+	// JSONNested and JSONMinify shape the bundles of the json generator.
+	JSONNested bool `yaml:"json_nested"`
+	JSONMinify bool `yaml:"json_minify"`
+	// TSDeclaration makes the ts generator write a .d.ts file.
+	TSDeclaration bool `yaml:"ts_declaration"`
+	// CSVDelimiter overrides the delimiter of CSV and TSV files.
+	CSVDelimiter string `yaml:"csv_delimiter"`
+	// Check is only set from the command line.
+	Check bool `yaml:"-"`
+}
+
+// loadConfig reads the config file at path. An empty path gives an empty
+// config.
+func loadConfig(path string) (config, error) {
+	c := config{}
+	if path == "" {
+		return c, nil
+	}
+
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	if err := yaml.UnmarshalStrict(byteValue, &c); err != nil {
+		return c, fmt.Errorf("%v: %v", path, err)
+	}
+	return c, nil
+}
+
+// setFlag overrides the config value matching f, which was set on the
+// command line.
+func (c *config) setFlag(f *flag.Flag) {
+	value := f.Value.String()
+	switch f.Name {
+	case "input":
+		c.Input = value
+	case "output":
+		c.Output = value
+	case "emit":
+		c.Emit = value
+	case "runtime":
+		c.Runtime = value
+	case "default-locale":
+		c.DefaultLocale = value
+	case "fallback-locale":
+		c.FallbackLocale = value
+	case "timestamp":
+		c.Timestamp, _ = strconv.ParseBool(value)
+	case "check":
+		c.Check, _ = strconv.ParseBool(value)
+	case "report-missing":
+		c.ReportMissing, _ = strconv.ParseBool(value)
+	case "report-format":
+		c.ReportFormat = value
+	case "strict":
+		c.Strict, _ = strconv.ParseBool(value)
+	case "duplicates":
+		c.Duplicates = value
+	case "aliases":
+		c.Aliases = value
+	case "key-collisions":
+		c.KeyCollisions = value
+	case "json-nested":
+		c.JSONNested, _ = strconv.ParseBool(value)
+	case "json-minify":
+		c.JSONMinify, _ = strconv.ParseBool(value)
+	case "ts-declaration":
+		c.TSDeclaration, _ = strconv.ParseBool(value)
+	case "csv-delimiter":
+		c.CSVDelimiter = value
+	}
+}
+
+// parseConfig fills in the defaults of unset values. It only rejects the
+// values that cannot be turned into localize.Options, localize.Generate
+// validates the others.
+func parseConfig(c config) (config, error) {
+	if c.Input == "" {
+		return c, errFlagInputNotSet
+	}
+	if c.Output == "" {
+		c.Output = defaultOutputDir
+	}
+
+	if c.Emit == "" {
+		c.Emit = localize.GeneratorGo
+	}
+	if _, _, ok := parseEmit(c.Emit); !ok {
+		return c, errFlagEmitInvalid
+	}
+
+	if c.Runtime == "" {
+		c.Runtime = localize.RuntimeImport
+	}
+	if c.ReportFormat == "" {
+		c.ReportFormat = localize.ReportFormatText
+	}
+	if c.Duplicates == "" {
+		c.Duplicates = localize.DuplicatesError
+	}
+	if c.KeyCollisions == "" {
+		c.KeyCollisions = localize.KeyCollisionsError
+	}
+
+	if _, ok := csvComma(c.CSVDelimiter); c.CSVDelimiter != "" && !ok {
+		return c, errFlagCSVDelimiterInvalid
+	}
+
+	if c.DefaultLocale == "" {
+		c.DefaultLocale = defaultLocaleName
+	}
+	if c.FallbackLocale == "" {
+		c.FallbackLocale = c.DefaultLocale
+	}
+
+	return c, nil
+}
+
+// loadAliases reads the YAML file at path, mapping keys to the constant
+// names to use for them. An empty path gives no aliases.
+func loadAliases(path string) (map[string]string, error) {
+	aliases := map[string]string{}
+	if path == "" {
+		return aliases, nil
+	}
+
+	byteValue, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(byteValue, &aliases); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return aliases, nil
+}
+
+// parseEmit splits the generators set with -emit, e.g. "go,json=web/locales",
+// into their names and the output directories of those followed by one. It
+// is not ok when a name is empty or repeated.
+func parseEmit(s string) ([]string, map[string]string, bool) {
+	var names []string
+	outputs := map[string]string{}
+	seen := map[string]struct{}{}
+	for _, field := range strings.Split(s, ",") {
+		name, output := strings.TrimSpace(field), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, output = strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+1:])
+			if output == "" {
+				return nil, nil, false
+			}
+			outputs[name] = output
+		}
+		if _, ok := seen[name]; ok || name == "" {
+			return nil, nil, false
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names, outputs, true
+}
+
+// csvComma returns the delimiter set with -csv-delimiter, where "\t" and
+// "tab" stand for a tab. It is not ok when s is empty or not a valid
+// delimiter.
+func csvComma(s string) (rune, bool) {
+	if s == `\t` || s == "tab" {
+		return '\t', true
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, false
+	}
+	return r, true
+}
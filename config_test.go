@@ -0,0 +1,208 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fitzix/go-localize/localize"
+)
+
+func Test_loadConfig(t *testing.T) {
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			args: args{"mock/config.yaml"},
+			want: config{
+				Input:          "examples/localizations_src",
+				Runtime:        localize.RuntimeStandalone,
+				DefaultLocale:  "es",
+				FallbackLocale: "en",
+			},
+		},
+		{
+			name: "no config",
+			args: args{""},
+			want: config{},
+		},
+		{
+			name:    "file not exist",
+			args:    args{"mock/non_exist.yaml"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			args:    args{"mock/aliases.yaml"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadConfig(tt.args.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadConfig() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       config
+		want    config
+		wantErr error
+	}{
+		{
+			name: "valid",
+			c:    config{Input: "input", Output: "output", Emit: "go,keys=web", Runtime: localize.RuntimeStandalone, DefaultLocale: "es", FallbackLocale: "en", ReportFormat: localize.ReportFormatJSON, Duplicates: localize.DuplicatesLast, KeyCollisions: localize.KeyCollisionsSuffix},
+			want: config{Input: "input", Output: "output", Emit: "go,keys=web", Runtime: localize.RuntimeStandalone, DefaultLocale: "es", FallbackLocale: "en", ReportFormat: localize.ReportFormatJSON, Duplicates: localize.DuplicatesLast, KeyCollisions: localize.KeyCollisionsSuffix},
+		},
+		{
+			name: "defaults",
+			c:    config{Input: "input"},
+			want: config{Input: "input", Output: defaultOutputDir, Emit: localize.GeneratorGo, Runtime: localize.RuntimeImport, DefaultLocale: "en", FallbackLocale: "en", ReportFormat: localize.ReportFormatText, Duplicates: localize.DuplicatesError, KeyCollisions: localize.KeyCollisionsError},
+		},
+		{
+			name: "fallback defaults to default locale",
+			c:    config{Input: "input", DefaultLocale: "es"},
+			want: config{Input: "input", Output: defaultOutputDir, Emit: localize.GeneratorGo, Runtime: localize.RuntimeImport, DefaultLocale: "es", FallbackLocale: "es", ReportFormat: localize.ReportFormatText, Duplicates: localize.DuplicatesError, KeyCollisions: localize.KeyCollisionsError},
+		},
+		{
+			name:    "invalid input",
+			c:       config{},
+			wantErr: errFlagInputNotSet,
+		},
+		{
+			name: "left to localize.Generate",
+			c:    config{Input: "input", Runtime: "vendored", Check: true, Timestamp: true},
+			want: config{Input: "input", Output: defaultOutputDir, Emit: localize.GeneratorGo, Runtime: "vendored", DefaultLocale: "en", FallbackLocale: "en", ReportFormat: localize.ReportFormatText, Duplicates: localize.DuplicatesError, KeyCollisions: localize.KeyCollisionsError, Check: true, Timestamp: true},
+		},
+		{
+			name:    "invalid emit",
+			c:       config{Input: "input", Emit: "go,,json"},
+			wantErr: errFlagEmitInvalid,
+		},
+		{
+			name:    "invalid csv delimiter",
+			c:       config{Input: "input", CSVDelimiter: ";;"},
+			wantErr: errFlagCSVDelimiterInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConfig(tt.c)
+			if err != tt.wantErr {
+				t.Errorf("parseConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConfig() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseEmit(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		want        []string
+		wantOutputs map[string]string
+		wantOk      bool
+	}{
+		{name: "go", s: "go", want: []string{"go"}, wantOutputs: map[string]string{}, wantOk: true},
+		{
+			name:        "outputs",
+			s:           "go, keys=web/keys",
+			want:        []string{"go", "keys"},
+			wantOutputs: map[string]string{"keys": "web/keys"},
+			wantOk:      true,
+		},
+		{name: "empty name", s: "go,"},
+		{name: "empty output", s: "keys="},
+		{name: "repeated", s: "go,go=other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, outputs, ok := parseEmit(tt.s)
+			if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(outputs, tt.wantOutputs) || ok != tt.wantOk {
+				t.Errorf("parseEmit() got = %v, %v, %v, want %v, %v, %v", got, outputs, ok, tt.want, tt.wantOutputs, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_csvComma(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		want   rune
+		wantOk bool
+	}{
+		{name: "semicolon", s: ";", want: ';', wantOk: true},
+		{name: "escaped tab", s: `\t`, want: '\t', wantOk: true},
+		{name: "tab", s: "tab", want: '\t', wantOk: true},
+		{name: "unicode", s: "¦", want: '¦', wantOk: true},
+		{name: "empty", s: ""},
+		{name: "several characters", s: ",;"},
+		{name: "quote", s: `"`},
+		{name: "newline", s: "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := csvComma(tt.s)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("csvComma() got = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_loadAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			path: "mock/aliases.yaml",
+			want: map[string]string{"customer_messages.hello": "LegacyCustomerHello"},
+		},
+		{
+			name: "no aliases",
+			path: "",
+			want: map[string]string{},
+		},
+		{
+			name:    "file not exist",
+			path:    "mock/non_exist.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadAliases(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadAliases() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadAliases() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
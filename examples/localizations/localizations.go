@@ -1,96 +1,38 @@
 // Code generated by go-localize; DO NOT EDIT.
-// This file was generated by robots at
-// 2019-12-19 20:40:24.756426604 &#43;0000 GMT m=&#43;0.000940957
+// Localizations hash: sha256:d34ae8e2c7c55c7fc18b8f2af5f50289af36836f6082b38cdfe918f1350c5990
 
 package localizations
 
-import (
-	"bytes"
-	"fmt"
-	"text/template"
-)
-
-var localizations = map[string]string{
-	"en.messages.hello": "hello",
-	"en.messages.hello_firstname_lastname": "Hello {{.firstname}} {{.lastname}}",
-	"en.messages.hello_my_name_is": "Hello my name is {{.name}}",
-	"en.messages.how_are_you": "How are you?",
-	"en.messages.whats_your_name": "What&#39;s your name?",
-	"es.customer.messages.hello": "hello customer!",
-	"es.messages.hello": "Hola",
-	"es.messages.hello_my_name_is": "Hola, mi nombre es {{.name}}",
-	"es.messages.how_are_you": "¿Cómo estás?",
-	"es.messages.whats_your_name": "¿Cuál es tu nombre?",
-}
-
-type Replacements map[string]interface{}
-
-type Localizer struct {
-	Locale         string
-	FallbackLocale string
-	Localizations  map[string]string
-}
-
-func New(locale string, fallbackLocale string) *Localizer {
-	t := &Localizer{Locale: locale, FallbackLocale: fallbackLocale}
-	t.Localizations = localizations
-	return t
-}
-
-func (t Localizer) SetLocales(locale, fallback string) Localizer {
-	t.Locale = locale
-	t.FallbackLocale = fallback
-	return t
-}
-
-func (t Localizer) SetLocale(locale string) Localizer {
-	t.Locale = locale
-	return t
-}
+var l = New("en", "en", localizations)
 
-func (t Localizer) SetFallbackLocale(fallback string) Localizer {
-	t.FallbackLocale = fallback
-	return t
+// Get returns the localization of key in the default locale.
+func Get(key Key, replacements ...*Replacements) string {
+	return l.Get(key, replacements...)
 }
 
-func (t Localizer) GetWithLocale(locale, key string, replacements ...*Replacements) string {
-	str, ok := t.Localizations[t.getLocalizationKey(locale, key)]
-	if !ok {
-		str, ok = t.Localizations[t.getLocalizationKey(t.FallbackLocale, key)]
-		if !ok {
-			return key
-		}
-	}
-	return t.replace(str, replacements...)
+// GetWithLocale returns the localization of key in locale.
+func GetWithLocale(locale string, key Key, replacements ...*Replacements) string {
+	return l.GetWithLocale(locale, key, replacements...)
 }
 
-func (t Localizer) Get(key string, replacements ...*Replacements) string {
-	str := t.GetWithLocale(t.Locale, key, replacements...)
-	return str
-}
-
-func (t Localizer) getLocalizationKey(locale string, key string) string {
-	return fmt.Sprintf("%v.%v", locale, key)
-}
-
-func (t Localizer) replace(str string, replacements ...*Replacements) string {
-	b := &bytes.Buffer{}
-	tmpl, err := template.New("").Parse(str)
-	if err != nil {
-		return str
-	}
-
-	replacementsMerge := Replacements{}
-	for _, replacement := range replacements {
-		for k, v := range *replacement {
-			replacementsMerge[k] = v
-		}
-	}
+const (
+	CustomerMessagesHello          Key = "customer.messages.hello"
+	MessagesHello                  Key = "messages.hello"
+	MessagesHelloFirstnameLastname Key = "messages.hello_firstname_lastname"
+	MessagesHelloMyNameIs          Key = "messages.hello_my_name_is"
+	MessagesHowAreYou              Key = "messages.how_are_you"
+	MessagesWhatsYourName          Key = "messages.whats_your_name"
+)
 
-	err = template.Must(tmpl, err).Execute(b, replacementsMerge)
-	if err != nil {
-		return str
-	}
-	buff := b.String()
-	return buff
+var localizations = map[string]string{
+	"en.messages.hello":                    "hello",
+	"en.messages.hello_firstname_lastname": "Hello {{.firstname}} {{.lastname}}",
+	"en.messages.hello_my_name_is":         "Hello my name is {{.name}}",
+	"en.messages.how_are_you":              "How are you?",
+	"en.messages.whats_your_name":          "What's your name?",
+	"es.customer.messages.hello":           "hello customer!",
+	"es.messages.hello":                    "Hola",
+	"es.messages.hello_my_name_is":         "Hola, mi nombre es {{.name}}",
+	"es.messages.how_are_you":              "¿Cómo estás?",
+	"es.messages.whats_your_name":          "¿Cuál es tu nombre?",
 }
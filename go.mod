@@ -1,9 +1,9 @@
 module github.com/fitzix/go-localize
 
-go 1.13
+go 1.17
 
 require (
 	github.com/BurntSushi/toml v0.3.1
-	github.com/iancoleman/strcase v0.1.3 // indirect
+	github.com/iancoleman/strcase v0.1.3
 	gopkg.in/yaml.v2 v2.2.7
 )
@@ -0,0 +1,93 @@
+// Package i18n is the runtime used by the packages go-localize generates.
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Key is a localization key without its locale prefix, e.g. "messages.hello".
+type Key string
+
+// Replacements are the values substituted into the {{.name}} placeholders of
+// a localization.
+type Replacements map[string]interface{}
+
+// Localizer looks up localizations for a locale, falling back to a second
+// locale when a key has no translation.
+type Localizer struct {
+	Locale         string
+	FallbackLocale string
+	Localizations  map[string]string
+}
+
+// New returns a Localizer for locale and fallbackLocale over localizations,
+// which is keyed by "<locale>.<key>".
+func New(locale string, fallbackLocale string, localizations map[string]string) *Localizer {
+	return &Localizer{
+		Locale:         locale,
+		FallbackLocale: fallbackLocale,
+		Localizations:  localizations,
+	}
+}
+
+func (t Localizer) SetLocales(locale, fallback string) Localizer {
+	t.Locale = locale
+	t.FallbackLocale = fallback
+	return t
+}
+
+func (t Localizer) SetLocale(locale string) Localizer {
+	t.Locale = locale
+	return t
+}
+
+func (t Localizer) SetFallbackLocale(fallback string) Localizer {
+	t.FallbackLocale = fallback
+	return t
+}
+
+// GetWithLocale returns the localization of key for locale. If there is none
+// the fallback locale is tried, and if that fails too the key itself is
+// returned.
+func (t Localizer) GetWithLocale(locale string, key Key, replacements ...*Replacements) string {
+	str, ok := t.Localizations[t.getLocalizationKey(locale, key)]
+	if !ok {
+		str, ok = t.Localizations[t.getLocalizationKey(t.FallbackLocale, key)]
+		if !ok {
+			return string(key)
+		}
+	}
+	return t.replace(str, replacements...)
+}
+
+// Get returns the localization of key for the Localizer's locale.
+func (t Localizer) Get(key Key, replacements ...*Replacements) string {
+	return t.GetWithLocale(t.Locale, key, replacements...)
+}
+
+func (t Localizer) getLocalizationKey(locale string, key Key) string {
+	return fmt.Sprintf("%v.%v", locale, key)
+}
+
+func (t Localizer) replace(str string, replacements ...*Replacements) string {
+	b := &bytes.Buffer{}
+	tmpl, err := template.New("").Parse(str)
+	if err != nil {
+		return str
+	}
+
+	replacementsMerge := Replacements{}
+	for _, replacement := range replacements {
+		for k, v := range *replacement {
+			replacementsMerge[k] = v
+		}
+	}
+
+	err = tmpl.Execute(b, replacementsMerge)
+	if err != nil {
+		return str
+	}
+	return b.String()
+}
@@ -0,0 +1,9 @@
+package i18n
+
+import _ "embed"
+
+// Source is the source code of i18n.go, which go-localize copies into the
+// packages it generates with a standalone runtime.
+//
+//go:embed i18n.go
+var Source []byte
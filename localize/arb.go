@@ -0,0 +1,151 @@
+package localize
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const arbFileExt = ".arb"
+
+// arbMetadata is the "@key" entry documenting the message "key".
+type arbMetadata struct {
+	Description  string                    `json:"description"`
+	Placeholders map[string]arbPlaceholder `json:"placeholders"`
+}
+
+type arbPlaceholder struct {
+	Type string `json:"type"`
+}
+
+// arbSelect matches the ICU plural and select messages, which the runtime
+// cannot render.
+var arbSelect = regexp.MustCompile(`\{\s*\w+\s*,\s*(plural|select|selectordinal)\s*,`)
+
+// arbDecoder decodes Flutter ARB files, named like the Flutter tools expect,
+// e.g. app_fr.arb.
+type arbDecoder struct{}
+
+// Decode implements Decoder.
+func (arbDecoder) Decode(value []byte, l *SourceFile, _ Options) error {
+	return parseARB(value, l)
+}
+
+// NameLocale implements NameLocaler, see arbFileLocale.
+func (arbDecoder) NameLocale(name string) (string, string, bool) {
+	return arbFileLocale(name)
+}
+
+var arbFileSuffix = regexp.MustCompile(`^(.+?)_([a-z]{2,3})(?:_([A-Z][a-z]{3}))?(?:_([A-Z]{2}|[0-9]{3}))?$`)
+
+// arbFileLocale returns the locale of a Flutter ARB file name, e.g. "fr" for
+// app_fr, "pt-BR" for app_pt_BR and "zh-Hant-TW" for app_zh_Hant_TW. A file
+// name without a locale, e.g. app or app_ui, uses its "@@locale", if any.
+func arbFileLocale(name string) (string, string, bool) {
+	match := arbFileSuffix.FindStringSubmatch(name)
+	if match == nil || !isLanguageCode(match[2]) {
+		if isLanguageCode(name) {
+			return "", "", false
+		}
+		return "", name, true
+	}
+	locale := match[2]
+	for _, subtag := range match[3:] {
+		if subtag != "" {
+			locale += "-" + subtag
+		}
+	}
+	return locale, match[1], true
+}
+
+var arbPlaceholderName = regexp.MustCompile(`\{[A-Za-z_][A-Za-z0-9_]*\}`)
+
+// arbPlaceholders turns the {name} placeholders of message into {{.name}}.
+// Only the template file of a Flutter app declares them in its metadata, so
+// they are converted whether they are declared or not. Those already inside
+// braces, e.g. {{name}}, are left alone.
+func arbPlaceholders(message string) string {
+	b := &strings.Builder{}
+	last := 0
+	for _, loc := range arbPlaceholderName.FindAllStringIndex(message, -1) {
+		start, end := loc[0], loc[1]
+		if start > 0 && message[start-1] == '{' || end < len(message) && message[end] == '}' {
+			continue
+		}
+		b.WriteString(message[last:start])
+		b.WriteString("{{." + message[start+1:end-1] + "}}")
+		last = end
+	}
+	b.WriteString(message[last:])
+	return b.String()
+}
+
+// parseARB decodes a Flutter ARB file. The "@@locale" entry gives the locale
+// of the file, and the description and placeholders of the "@key" entries
+// document their message. The {name} placeholders of a message become
+// {{.name}}, declared or not, and ICU plural and select messages are
+// skipped.
+func parseARB(value []byte, l *SourceFile) error {
+	entries := map[string]json.RawMessage{}
+	if err := json.Unmarshal(value, &entries); err != nil {
+		return err
+	}
+
+	l.Localizations = map[string]string{}
+	l.Descriptions = map[string]string{}
+	l.Placeholders = map[string]map[string]string{}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw := entries[key]
+		switch {
+		case key == "@@locale":
+			if err := json.Unmarshal(raw, &l.Locale); err != nil {
+				return fmt.Errorf("@@locale: %v", err)
+			}
+		case strings.HasPrefix(key, "@@"):
+			// Other global metadata, e.g. @@last_modified.
+		case strings.HasPrefix(key, "@"):
+			if _, ok := entries[key[1:]]; !ok {
+				l.Warnings = append(l.Warnings, fmt.Sprintf("%v: metadata of an undefined message", key))
+			}
+		default:
+			var message string
+			if err := json.Unmarshal(raw, &message); err != nil {
+				return fmt.Errorf("%v: %v", key, err)
+			}
+			if arbSelect.MatchString(message) {
+				l.Warnings = append(l.Warnings, fmt.Sprintf("%v: skipping ICU plural or select message", key))
+				continue
+			}
+
+			metadata := arbMetadata{}
+			if raw, ok := entries["@"+key]; ok {
+				if err := json.Unmarshal(raw, &metadata); err != nil {
+					return fmt.Errorf("@%v: %v", key, err)
+				}
+			}
+
+			placeholders := map[string]string{}
+			for name, placeholder := range metadata.Placeholders {
+				placeholders[name] = placeholder.Type
+			}
+
+			l.Localizations[key] = arbPlaceholders(message)
+			if metadata.Description != "" {
+				l.Descriptions[key] = metadata.Description
+			}
+			if len(placeholders) > 0 {
+				l.Placeholders[key] = placeholders
+			}
+		}
+	}
+	return nil
+}
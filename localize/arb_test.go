@@ -0,0 +1,110 @@
+package localize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_arbFileLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		wantLocale string
+		wantBase   string
+		wantOk     bool
+	}{
+		{name: "language", file: "app_fr", wantLocale: "fr", wantBase: "app", wantOk: true},
+		{name: "region", file: "app_pt_BR", wantLocale: "pt-BR", wantBase: "app", wantOk: true},
+		{name: "script and region", file: "app_zh_Hant_TW", wantLocale: "zh-Hant-TW", wantBase: "app", wantOk: true},
+		{name: "underscored base", file: "intl_messages_de", wantLocale: "de", wantBase: "intl_messages", wantOk: true},
+		{name: "without locale", file: "app", wantLocale: "", wantBase: "app", wantOk: true},
+		{name: "not a language", file: "app_ui", wantLocale: "", wantBase: "app_ui", wantOk: true},
+		{name: "locale only", file: "fr", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, base, ok := arbFileLocale(tt.file)
+			if locale != tt.wantLocale || base != tt.wantBase || ok != tt.wantOk {
+				t.Errorf("arbFileLocale() got = %v, %v, %v, want %v, %v, %v",
+					locale, base, ok, tt.wantLocale, tt.wantBase, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_parseARB(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *SourceFile
+		wantErr bool
+	}{
+		{
+			name: "metadata",
+			value: `{
+				"@@locale": "de",
+				"greeting": "Hallo {name}, du hast {count} Nachrichten",
+				"@greeting": {
+					"description": "Greets the user",
+					"placeholders": {"name": {"type": "String"}, "count": {"type": "int"}}
+				},
+				"@orphan": {"description": "Nothing"}
+			}`,
+			want: &SourceFile{
+				Locale:        "de",
+				Localizations: map[string]string{"greeting": "Hallo {{.name}}, du hast {{.count}} Nachrichten"},
+				Descriptions:  map[string]string{"greeting": "Greets the user"},
+				Placeholders:  map[string]map[string]string{"greeting": {"name": "String", "count": "int"}},
+				Warnings:      []string{"@orphan: metadata of an undefined message"},
+			},
+		},
+		{
+			name:  "translation without metadata",
+			value: `{"@@locale": "fr", "hello": "Salut {name}, {user_2} et {0}", "braces": "{{kept}}"}`,
+			want: &SourceFile{
+				Locale:        "fr",
+				Localizations: map[string]string{"hello": "Salut {{.name}}, {{.user_2}} et {0}", "braces": "{{kept}}"},
+				Descriptions:  map[string]string{},
+				Placeholders:  map[string]map[string]string{},
+			},
+		},
+		{
+			name:  "icu plural",
+			value: `{"items": "{count, plural, one{1 item} other{{count} items}}"}`,
+			want: &SourceFile{
+				Localizations: map[string]string{},
+				Descriptions:  map[string]string{},
+				Placeholders:  map[string]map[string]string{},
+				Warnings:      []string{"items: skipping ICU plural or select message"},
+			},
+		},
+		{
+			name:    "message not a string",
+			value:   `{"hello": {"nested": "value"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid metadata",
+			value:   `{"hello": "Hello", "@hello": "not an object"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			value:   `{"hello": `,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseARB([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseARB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseARB() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package localize
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	tarFileExt   = ".tar"
+	tarGzFileExt = ".tar.gz"
+	tgzFileExt   = ".tgz"
+
+	// maxArchiveSize bounds the size of an archive, and the total size of
+	// the files it holds once decompressed, nested archives included, so
+	// that an archive bomb cannot exhaust memory.
+	maxArchiveSize = 64 << 20
+	// maxArchiveDepth bounds how deep archives are nested in one another.
+	maxArchiveDepth = 4
+)
+
+// inputFS is the file system of the source files. Archives are read in
+// memory, and the files they hold appear under the path of the archive, e.g.
+// "sub/archive.zip/en/messages.json", or "sub/archive.zip/nested.tar.gz/..."
+// for nested archives. Nothing is ever extracted to disk.
+type inputFS struct {
+	fs.FS
+	// archived holds the files of the mounted archives by path.
+	archived map[string][]byte
+}
+
+func newInputFS(fsys fs.FS) *inputFS {
+	return &inputFS{FS: fsys, archived: map[string][]byte{}}
+}
+
+func (f *inputFS) Open(name string) (fs.File, error) {
+	if data, ok := f.archived[name]; ok {
+		return &memFile{Reader: bytes.NewReader(data), name: path.Base(name)}, nil
+	}
+	return f.FS.Open(name)
+}
+
+func (f *inputFS) ReadFile(name string) ([]byte, error) {
+	if data, ok := f.archived[name]; ok {
+		return data, nil
+	}
+	return fs.ReadFile(f.FS, name)
+}
+
+// mount reads the archive at name and returns the sorted paths of the files
+// it holds, including those of the archives nested in it.
+func (f *inputFS) mount(name string) ([]string, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveSize {
+		return nil, fmt.Errorf("%v: archive larger than %d bytes", name, maxArchiveSize)
+	}
+
+	budget := int64(maxArchiveSize)
+	return f.extract(name, data, 0, &budget)
+}
+
+// extract mounts the files of the archive name, holding data, and returns
+// their sorted paths. Nested archives are extracted in turn, and they all
+// share the budget of bytes left to decompress.
+func (f *inputFS) extract(name string, data []byte, depth int, budget *int64) ([]string, error) {
+	if depth > maxArchiveDepth {
+		return nil, fmt.Errorf("%v: archives nested more than %d deep", name, maxArchiveDepth)
+	}
+
+	files, err := readArchive(name, data, budget)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for entry, content := range files {
+		p := path.Join(name, entry)
+		if isArchive(p) {
+			nested, err := f.extract(p, content, depth+1, budget)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, nested...)
+			continue
+		}
+		f.archived[p] = content
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// isArchive reports whether name is an archive whose files are sources.
+func isArchive(name string) bool {
+	for _, ext := range []string{zipFileExt, tarFileExt, tarGzFileExt, tgzFileExt} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// readArchive returns the content of the regular files of the archive name,
+// holding data, by path. It rejects paths that would escape the archive, and
+// fails once more than budget bytes were decompressed.
+func readArchive(name string, data []byte, budget *int64) (map[string][]byte, error) {
+	switch {
+	case strings.HasSuffix(name, zipFileExt):
+		return readZip(data, budget)
+	case strings.HasSuffix(name, tarFileExt):
+		return readTar(bytes.NewReader(data), budget)
+	default:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return readTar(gz, budget)
+	}
+}
+
+func readZip(data []byte, budget *int64) (map[string][]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		if strings.HasSuffix(file.Name, "/") || !file.Mode().IsRegular() {
+			continue
+		}
+		if err := checkArchivePath(file.Name, files); err != nil {
+			return nil, err
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(budgetReader{r: rc, budget: budget})
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", file.Name, err)
+		}
+		files[file.Name] = content
+	}
+	return files, nil
+}
+
+func readTar(r io.Reader, budget *int64) (map[string][]byte, error) {
+	reader := tar.NewReader(budgetReader{r: r, budget: budget})
+
+	files := map[string][]byte{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !header.FileInfo().Mode().IsRegular() {
+			continue
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		if err := checkArchivePath(name, files); err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", name, err)
+		}
+		files[name] = content
+	}
+}
+
+// checkArchivePath checks that the file name of an archive stays within the
+// archive and is not already in files.
+func checkArchivePath(name string, files map[string][]byte) error {
+	if !fs.ValidPath(name) || strings.Contains(name, `\`) {
+		return fmt.Errorf("invalid file path %q", name)
+	}
+	if _, ok := files[name]; ok {
+		return fmt.Errorf("%v appears twice", name)
+	}
+	return nil
+}
+
+// budgetReader reads from r, failing once it read more than budget bytes,
+// whatever the headers of the archive say.
+type budgetReader struct {
+	r      io.Reader
+	budget *int64
+}
+
+func (b budgetReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	*b.budget -= int64(n)
+	if *b.budget < 0 {
+		return n, fmt.Errorf("archive larger than %d bytes once decompressed", maxArchiveSize)
+	}
+	return n, err
+}
+
+// memFile is a file of an archive read in memory.
+type memFile struct {
+	*bytes.Reader
+	name string
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *memFile) Close() error               { return nil }
+func (f *memFile) Name() string               { return f.name }
+func (f *memFile) Mode() fs.FileMode          { return 0444 }
+func (f *memFile) ModTime() time.Time         { return time.Time{} }
+func (f *memFile) IsDir() bool                { return false }
+func (f *memFile) Sys() interface{}           { return nil }
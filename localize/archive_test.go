@@ -0,0 +1,256 @@
+package localize
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_getLocalizationFiles_archive(t *testing.T) {
+	fsys := newInputFS(os.DirFS("mock/archive"))
+	files, err := getLocalizationFiles(Options{Logger: log.Default()}, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFiles := []string{
+		"en.json",
+		"exports.tar.gz/legal.zip/legal/de.json",
+		"exports.tar.gz/messages/fr.json",
+		"translations.zip/messages/en.json",
+		"translations.zip/messages/es.json",
+	}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Fatalf("getLocalizationFiles() got = %v, want %v", files, wantFiles)
+	}
+
+	cat, err := generateLocalizations(Options{Duplicates: DuplicatesError, Logger: log.Default()}, fsys, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"en.bye":            "Bye",
+		"de.legal.terms":    "AGB",
+		"fr.messages.hello": "Bonjour",
+		"en.messages.hello": "Hello",
+		"es.messages.hello": "Hola",
+	}
+	if !reflect.DeepEqual(cat.Localizations, want) {
+		t.Errorf("generateLocalizations() got = %v, want %v", cat.Localizations, want)
+	}
+
+	info, err := fs.Stat(fsys, "translations.zip/messages/es.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "es.json" || info.Size() != int64(len("{\"hello\": \"Hola\"}\n")) {
+		t.Errorf("Stat() got = %v, %d", info.Name(), info.Size())
+	}
+}
+
+// archiveFile is a file of an archive built by a test.
+type archiveFile struct {
+	name    string
+	content string
+}
+
+func zipArchive(t *testing.T, files ...archiveFile) []byte {
+	b := &bytes.Buffer{}
+	w := zip.NewWriter(b)
+	for _, file := range files {
+		f, err := w.Create(file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func tarArchive(t *testing.T, files ...archiveFile) []byte {
+	b := &bytes.Buffer{}
+	w := tar.NewWriter(b)
+	for _, file := range files {
+		header := &tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content)), Typeflag: tar.TypeReg}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func gzipData(t *testing.T, data []byte) []byte {
+	b := &bytes.Buffer{}
+	w := gzip.NewWriter(b)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func Test_readArchive(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		data    func(t *testing.T) []byte
+		budget  int64
+		want    map[string][]byte
+		wantErr bool
+	}{
+		{
+			name:    "zip directories",
+			archive: "a.zip",
+			data: func(t *testing.T) []byte {
+				return zipArchive(t, archiveFile{"en/", ""}, archiveFile{"en/messages.json", "{}"})
+			},
+			budget: 2,
+			want:   map[string][]byte{"en/messages.json": []byte("{}")},
+		},
+		{
+			name:    "tar",
+			archive: "a.tar",
+			data: func(t *testing.T) []byte {
+				return tarArchive(t, archiveFile{"./en/messages.json", "{}"})
+			},
+			budget: maxArchiveSize,
+			want:   map[string][]byte{"en/messages.json": []byte("{}")},
+		},
+		{
+			name:    "tgz",
+			archive: "a.tgz",
+			data: func(t *testing.T) []byte {
+				return gzipData(t, tarArchive(t, archiveFile{"en.json", "{}"}))
+			},
+			budget: maxArchiveSize,
+			want:   map[string][]byte{"en.json": []byte("{}")},
+		},
+		{
+			name:    "zip slip",
+			archive: "a.zip",
+			data: func(t *testing.T) []byte {
+				return zipArchive(t, archiveFile{"../../etc/en.json", "{}"})
+			},
+			budget:  maxArchiveSize,
+			wantErr: true,
+		},
+		{
+			name:    "tar slip",
+			archive: "a.tar.gz",
+			data: func(t *testing.T) []byte {
+				return gzipData(t, tarArchive(t, archiveFile{"/etc/en.json", "{}"}))
+			},
+			budget:  maxArchiveSize,
+			wantErr: true,
+		},
+		{
+			name:    "backslashes",
+			archive: "a.zip",
+			data: func(t *testing.T) []byte {
+				return zipArchive(t, archiveFile{`..\en.json`, "{}"})
+			},
+			budget:  maxArchiveSize,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate file",
+			archive: "a.tar",
+			data: func(t *testing.T) []byte {
+				return tarArchive(t, archiveFile{"en.json", "{}"}, archiveFile{"./en.json", "{}"})
+			},
+			budget:  maxArchiveSize,
+			wantErr: true,
+		},
+		{
+			name:    "zip over budget",
+			archive: "a.zip",
+			data: func(t *testing.T) []byte {
+				return zipArchive(t, archiveFile{"en.json", "{}"})
+			},
+			budget:  1,
+			wantErr: true,
+		},
+		{
+			name:    "tar.gz over budget",
+			archive: "a.tar.gz",
+			data: func(t *testing.T) []byte {
+				return gzipData(t, tarArchive(t, archiveFile{"en.json", "{}"}))
+			},
+			budget:  512,
+			wantErr: true,
+		},
+		{
+			name:    "not gzip",
+			archive: "a.tar.gz",
+			data: func(t *testing.T) []byte {
+				return []byte("not gzip")
+			},
+			budget:  maxArchiveSize,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := tt.budget
+			got, err := readArchive(tt.archive, tt.data(t), &budget)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readArchive() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readArchive() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_inputFS_mount(t *testing.T) {
+	nested := zipArchive(t, archiveFile{"en.json", "{}"})
+	for i := 0; i <= maxArchiveDepth; i++ {
+		nested = zipArchive(t, archiveFile{"nested.zip", string(nested)})
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name:    "not an archive",
+			data:    []byte("not a zip"),
+			wantErr: true,
+		},
+		{
+			name:    "nested too deep",
+			data:    nested,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := newInputFS(fstest.MapFS{"archive.zip": {Data: tt.data}})
+			if _, err := fsys.mount("archive.zip"); (err != nil) != tt.wantErr {
+				t.Errorf("mount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package localize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// GeneratorJSON is the name of the generator of the JSON bundles.
+const GeneratorJSON = "json"
+
+// renderJSONBundles renders a "<locale>.json" bundle in dir for every locale,
+// holding every key without its locale prefix. Fallbacks are resolved as the
+// generated Go package does: keys the locale has no translation for get that
+// of o.FallbackLocale, or the key itself when it has none either. Keys are
+// nested by their dotted parts when o.JSONNested is set.
+func renderJSONBundles(dir string, cat *Catalog, o Options) ([]File, error) {
+	var files []File
+	for _, locale := range getLocales(cat.Localizations) {
+		values := make(map[string]string, len(cat.Keys))
+		for _, key := range cat.Keys {
+			value, ok := cat.Localizations[locale+"."+key]
+			if !ok {
+				value, ok = cat.Localizations[o.FallbackLocale+"."+key]
+			}
+			if !ok {
+				value = key
+			}
+			values[key] = value
+		}
+
+		var bundle interface{} = values
+		if o.JSONNested {
+			nested, err := nestKeys(values)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v", locale, err)
+			}
+			bundle = nested
+		}
+
+		b := &bytes.Buffer{}
+		enc := json.NewEncoder(b)
+		enc.SetEscapeHTML(false)
+		if !o.JSONMinify {
+			enc.SetIndent("", "  ")
+		}
+		if err := enc.Encode(bundle); err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Join(dir, locale+".json"), Content: b.Bytes()})
+	}
+	return files, nil
+}
+
+// nestKeys turns dotted keys into nested objects, e.g. "checkout.pay" into
+// {"checkout": {"pay": ...}}. A key cannot both have a value and nest
+// others, e.g. "checkout" and "checkout.pay".
+func nestKeys(values map[string]string) (map[string]interface{}, error) {
+	nested := map[string]interface{}{}
+	for key, value := range values {
+		parts := strings.Split(key, ".")
+		parent := nested
+		for i, part := range parts[:len(parts)-1] {
+			switch child := parent[part].(type) {
+			case nil:
+				m := map[string]interface{}{}
+				parent[part] = m
+				parent = m
+			case map[string]interface{}:
+				parent = child
+			default:
+				return nil, fmt.Errorf("key %q has a value and nests %q", strings.Join(parts[:i+1], "."), key)
+			}
+		}
+
+		last := parts[len(parts)-1]
+		if _, ok := parent[last].(map[string]interface{}); ok {
+			return nil, fmt.Errorf("key %q has a value and nests other keys", key)
+		}
+		parent[last] = value
+	}
+	return nested, nil
+}
@@ -0,0 +1,76 @@
+package localize
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_renderJSONBundles(t *testing.T) {
+	cat := &Catalog{
+		Localizations: map[string]string{
+			"en.checkout.pay":   "Pay <now>",
+			"en.checkout.title": "Checkout",
+			"en.hello":          "Hello {{.name}}",
+			"es.checkout.pay":   "Pagar",
+			"fr.hello":          "Bonjour {{.name}}",
+		},
+		Keys: []string{"checkout.pay", "checkout.title", "hello"},
+	}
+	tests := []struct {
+		name    string
+		cat     *Catalog
+		o       Options
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "flat",
+			cat:  cat,
+			o:    Options{FallbackLocale: "en"},
+			want: map[string]string{
+				"en.json": "{\n  \"checkout.pay\": \"Pay <now>\",\n  \"checkout.title\": \"Checkout\",\n  \"hello\": \"Hello {{.name}}\"\n}\n",
+				"es.json": "{\n  \"checkout.pay\": \"Pagar\",\n  \"checkout.title\": \"Checkout\",\n  \"hello\": \"Hello {{.name}}\"\n}\n",
+				"fr.json": "{\n  \"checkout.pay\": \"Pay <now>\",\n  \"checkout.title\": \"Checkout\",\n  \"hello\": \"Bonjour {{.name}}\"\n}\n",
+			},
+		},
+		{
+			name: "nested minified",
+			cat:  cat,
+			o:    Options{FallbackLocale: "es", JSONNested: true, JSONMinify: true},
+			want: map[string]string{
+				"en.json": `{"checkout":{"pay":"Pay <now>","title":"Checkout"},"hello":"Hello {{.name}}"}` + "\n",
+				"es.json": `{"checkout":{"pay":"Pagar","title":"checkout.title"},"hello":"hello"}` + "\n",
+				"fr.json": `{"checkout":{"pay":"Pagar","title":"checkout.title"},"hello":"Bonjour {{.name}}"}` + "\n",
+			},
+		},
+		{
+			name: "nested conflict",
+			cat: &Catalog{
+				Localizations: map[string]string{"en.checkout": "Checkout", "en.checkout.pay": "Pay"},
+				Keys:          []string{"checkout", "checkout.pay"},
+			},
+			o:       Options{FallbackLocale: "en", JSONNested: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := renderJSONBundles("web", tt.cat, tt.o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("renderJSONBundles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			got := map[string]string{}
+			for _, file := range files {
+				if filepath.Dir(file.Path) != "web" {
+					t.Errorf("renderJSONBundles() file %v is not in web", file.Path)
+				}
+				got[filepath.Base(file.Path)] = string(file.Content)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderJSONBundles() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
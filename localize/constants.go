@@ -0,0 +1,91 @@
+package localize
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	// KeyCollisionsError fails Generate when keys generate the same constant.
+	KeyCollisionsError = "error"
+	// KeyCollisionsSuffix adds a numeric suffix to the constants of the keys
+	// after the first one, in sorted order.
+	KeyCollisionsSuffix = "suffix"
+)
+
+// reservedNames are declared by the generated package itself, in either
+// runtime mode, so no key constant may use them.
+var reservedNames = []string{"Get", "GetWithLocale", "Key", "Localizer", "New", "Replacements"}
+
+// keyConstants names the constant of every key, returning a map of constant
+// name to key. Names are the CamelCase key unless aliased. When two keys get
+// the same name, collisions decides whether that is an error or whether the
+// later key, in sorted order, gets a numeric suffix.
+func keyConstants(keys []string, aliases map[string]string, collisions string) (map[string]string, error) {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	constants := make(map[string]string, len(sorted))
+	owners := map[string]string{}
+	for _, name := range reservedNames {
+		owners[name] = ""
+	}
+
+	// Aliases are claimed first so that they never get a suffix.
+	for _, key := range sorted {
+		name, ok := aliases[key]
+		if !ok {
+			continue
+		}
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return nil, fmt.Errorf("alias of key %q: %q is not a valid exported Go identifier", key, name)
+		}
+		if err := claimName(owners, name, key); err != nil {
+			return nil, err
+		}
+		constants[name] = key
+	}
+
+	for _, key := range sorted {
+		if _, ok := aliases[key]; ok {
+			continue
+		}
+
+		name := strcase.ToCamel(key)
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("key %q: %q is not a valid Go identifier", key, name)
+		}
+
+		if collisions == KeyCollisionsSuffix {
+			base := name
+			for i := 2; ; i++ {
+				if _, taken := owners[name]; !taken {
+					break
+				}
+				name = base + strconv.Itoa(i)
+			}
+		}
+		if err := claimName(owners, name, key); err != nil {
+			return nil, err
+		}
+		constants[name] = key
+	}
+
+	return constants, nil
+}
+
+func claimName(owners map[string]string, name, key string) error {
+	owner, taken := owners[name]
+	if !taken {
+		owners[name] = key
+		return nil
+	}
+	if owner == "" {
+		return fmt.Errorf("key %q: the constant %v is reserved by the generated package, add an alias for the key", key, name)
+	}
+	return fmt.Errorf("keys %q and %q both generate the constant %v, add an alias for one of them or use -key-collisions suffix", owner, key, name)
+}
@@ -0,0 +1,113 @@
+package localize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_keyConstants(t *testing.T) {
+	type args struct {
+		keys       []string
+		aliases    map[string]string
+		collisions string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			args: args{
+				keys:       []string{"messages.hello", "customer.messages.hello"},
+				collisions: KeyCollisionsError,
+			},
+			want: map[string]string{
+				"MessagesHello":         "messages.hello",
+				"CustomerMessagesHello": "customer.messages.hello",
+			},
+		},
+		{
+			name: "collision",
+			args: args{
+				keys:       []string{"customer_messages.hello", "customer.messages.hello"},
+				collisions: KeyCollisionsError,
+			},
+			wantErr: true,
+		},
+		{
+			name: "collision suffix",
+			args: args{
+				keys:       []string{"customer_messages.hello", "customer.messages.hello", "customer.messages_hello"},
+				collisions: KeyCollisionsSuffix,
+			},
+			want: map[string]string{
+				"CustomerMessagesHello":  "customer.messages.hello",
+				"CustomerMessagesHello2": "customer.messages_hello",
+				"CustomerMessagesHello3": "customer_messages.hello",
+			},
+		},
+		{
+			name: "collision alias",
+			args: args{
+				keys:       []string{"customer_messages.hello", "customer.messages.hello"},
+				aliases:    map[string]string{"customer_messages.hello": "LegacyHello"},
+				collisions: KeyCollisionsError,
+			},
+			want: map[string]string{
+				"CustomerMessagesHello": "customer.messages.hello",
+				"LegacyHello":           "customer_messages.hello",
+			},
+		},
+		{
+			name: "alias takes precedence over suffix",
+			args: args{
+				keys:       []string{"a.hello", "hello"},
+				aliases:    map[string]string{"hello": "AHello"},
+				collisions: KeyCollisionsSuffix,
+			},
+			want: map[string]string{
+				"AHello":  "hello",
+				"AHello2": "a.hello",
+			},
+		},
+		{
+			name: "invalid alias",
+			args: args{
+				keys:       []string{"hello"},
+				aliases:    map[string]string{"hello": "hello world"},
+				collisions: KeyCollisionsError,
+			},
+			wantErr: true,
+		},
+		{
+			name: "reserved",
+			args: args{
+				keys:       []string{"get"},
+				collisions: KeyCollisionsError,
+			},
+			wantErr: true,
+		},
+		{
+			name: "reserved suffix",
+			args: args{
+				keys:       []string{"get"},
+				collisions: KeyCollisionsSuffix,
+			},
+			want: map[string]string{"Get2": "get"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keyConstants(tt.args.keys, tt.args.aliases, tt.args.collisions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("keyConstants() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keyConstants() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
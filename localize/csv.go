@@ -0,0 +1,131 @@
+package localize
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const tsvFileExt = ".tsv"
+
+// csvDescriptionColumns are the header names of the column documenting the
+// keys of a wide CSV file.
+var csvDescriptionColumns = map[string]struct{}{
+	"description": {},
+	"comment":     {},
+	"context":     {},
+}
+
+// csvDecoder decodes CSV files delimited by its rune, or by
+// Options.CSVDelimiter when set, see decodeCSV.
+type csvDecoder rune
+
+// Decode implements Decoder.
+func (comma csvDecoder) Decode(value []byte, l *SourceFile, o Options) error {
+	if o.CSVDelimiter != 0 {
+		return decodeCSV(value, l, o.CSVDelimiter)
+	}
+	return decodeCSV(value, l, rune(comma))
+}
+
+// decodeCSV decodes a CSV file delimited by comma. A file whose header row
+// starts with a "key" column holds a column per locale, named by the header,
+// and optionally a "description" column, e.g. "key,en,es,description".
+// Otherwise, each row holds a key and its value, and any other column is
+// ignored, with an optional "key,value" header. Empty cells of wide files are
+// untranslated and skipped. A leading UTF-8 byte order mark is ignored.
+func decodeCSV(value []byte, l *SourceFile, comma rune) error {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(value, []byte("\xEF\xBB\xBF"))))
+	r.Comma = comma
+
+	var header []string
+	localizations := map[string]string{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := r.FieldPos(0)
+
+		if header == nil && len(localizations) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "key") {
+			if len(record) == 2 && strings.EqualFold(strings.TrimSpace(record[1]), "value") {
+				// The header of a key and value file.
+				continue
+			}
+			header = record
+			if err := checkCSVHeader(header); err != nil {
+				return fmt.Errorf("line %d: %v", line, err)
+			}
+			l.Locales = map[string]map[string]string{}
+			l.Descriptions = map[string]string{}
+			continue
+		}
+		if len(record) < 2 {
+			return fmt.Errorf("line %d: expected a key and a value, found %d columns", line, len(record))
+		}
+
+		key := record[0]
+		if key == "" {
+			return fmt.Errorf("line %d: empty key", line)
+		}
+		if header == nil {
+			if _, ok := localizations[key]; ok {
+				return fmt.Errorf("line %d: duplicate key %q", line, key)
+			}
+			localizations[key] = record[1]
+			continue
+		}
+
+		if _, ok := localizations[key]; ok {
+			return fmt.Errorf("line %d: duplicate key %q", line, key)
+		}
+		localizations[key] = ""
+		for i, column := range header[1:] {
+			column = strings.TrimSpace(column)
+			value := record[i+1]
+			if _, ok := csvDescriptionColumns[strings.ToLower(column)]; ok {
+				if value != "" {
+					l.Descriptions[key] = value
+				}
+				continue
+			}
+			if value == "" {
+				continue
+			}
+			if l.Locales[column] == nil {
+				l.Locales[column] = map[string]string{}
+			}
+			l.Locales[column][key] = value
+		}
+	}
+
+	if header == nil {
+		l.Localizations = localizations
+	}
+	return nil
+}
+
+// checkCSVHeader checks that the locale columns of header are named and
+// unique.
+func checkCSVHeader(header []string) error {
+	if len(header) < 2 {
+		return fmt.Errorf("header has no locale column")
+	}
+	seen := map[string]struct{}{}
+	for i, column := range header[1:] {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return fmt.Errorf("column %d of the header is empty", i+2)
+		}
+		if _, ok := seen[column]; ok {
+			return fmt.Errorf("column %q appears twice in the header", column)
+		}
+		seen[column] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package localize
+
+import (
+	"log"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_getLocalizationsFromFile_csvDelimiter(t *testing.T) {
+	o := Options{CSVDelimiter: ';', Logger: log.Default()}
+	got, err := getLocalizationsFromFile(o, os.DirFS("mock/csv_semicolon"), "messages.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"en.messages.hello": "Hello, you",
+		"de.messages.hello": "Hallo",
+	}
+	if !reflect.DeepEqual(got.Localizations, want) {
+		t.Errorf("getLocalizationsFromFile() got = %v, want %v", got.Localizations, want)
+	}
+}
@@ -0,0 +1,221 @@
+package localize
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+)
+
+// sniffLen is the number of bytes at the start of a file given to sniffers.
+const sniffLen = 512
+
+// SourceFile is the decoded content of a source file, filled in by its
+// Decoder.
+type SourceFile struct {
+	// Locale is the locale declared by the file itself, if any. The locale
+	// of a DirLocaler directory or NameLocaler file name takes precedence
+	// over it.
+	Locale string
+	// Localizations maps the keys of the file to their values, with nested
+	// keys joined by dots.
+	Localizations map[string]string
+	// Descriptions document the keys of the file, e.g. XLIFF notes.
+	Descriptions map[string]string
+	// Placeholders maps keys to the types of their placeholders, by name,
+	// for formats that declare them, e.g. ARB.
+	Placeholders map[string]map[string]string
+	// Locales holds the localizations of files defining several locales,
+	// e.g. CSV files with a column per locale, by locale. Localizations and
+	// Locale are unused for them.
+	Locales map[string]map[string]string
+	// Warnings are problems that did not prevent decoding the file, such as
+	// skipped entries.
+	Warnings []string
+}
+
+// Decoder decodes the source files of a format.
+type Decoder interface {
+	// Decode fills in f from data, the content of a source file, following
+	// the options of o that apply to the format, e.g. o.CSVDelimiter.
+	// Problems that do not prevent decoding the file are added to
+	// f.Warnings.
+	Decode(data []byte, f *SourceFile, o Options) error
+}
+
+// DecoderFunc is a function used as a Decoder.
+type DecoderFunc func(data []byte, f *SourceFile, o Options) error
+
+// Decode calls fn(data, f, o).
+func (fn DecoderFunc) Decode(data []byte, f *SourceFile, o Options) error {
+	return fn(data, f, o)
+}
+
+// decodeFunc is a function used as a Decoder, for the built-in formats that
+// have no options.
+type decodeFunc func(data []byte, f *SourceFile) error
+
+// Decode calls fn(data, f).
+func (fn decodeFunc) Decode(data []byte, f *SourceFile, _ Options) error {
+	return fn(data, f)
+}
+
+// Matcher is implemented by decoders that only decode some of the files with
+// their extension, e.g. Android resources, which are the XML files of values
+// directories. The other files are left to the sniffers, if any, or skipped.
+type Matcher interface {
+	// Match reports whether the file at the slash-separated path name is in
+	// the format of the decoder.
+	Match(name string) bool
+}
+
+// DirLocaler is implemented by decoders of the formats whose platform takes
+// the locale of a file from its directory, e.g. values-es for Android.
+type DirLocaler interface {
+	// DirLocale returns the locale of the files of the directory named dir,
+	// and ok when dir follows the convention, in which case it is not part
+	// of their keys. An empty locale is the directory of the base
+	// localization, whose files use the default locale unless they declare
+	// their own.
+	DirLocale(dir string) (locale string, ok bool)
+}
+
+// NameLocaler is implemented by decoders of the formats whose platform takes
+// the locale of a file from its name, e.g. messages_es.properties for Java.
+type NameLocaler interface {
+	// NameLocale returns the locale of the file named name, without its
+	// extension, the name without its locale, which prefixes the keys of
+	// the file, and ok when name follows the convention. An empty locale is
+	// the base localization, which uses the default locale unless the file
+	// declares its own.
+	NameLocale(name string) (locale, base string, ok bool)
+}
+
+type sniffer struct {
+	sniff   func(head []byte) bool
+	decoder Decoder
+}
+
+// registry holds the decoders of every supported source file format, by
+// extension, and the decoders recognizing their files by content. File
+// discovery and parsing are both driven by it, so registering a decoder is
+// all that is needed for its files to be picked up.
+var registry = struct {
+	sync.RWMutex
+	extensions map[string]Decoder
+	sniffers   []sniffer
+}{extensions: map[string]Decoder{
+	jsonFileExt:        decodeFunc(parseJSON),
+	yamlFileExt:        decodeFunc(parseYAML),
+	ymlFileExt:         decodeFunc(parseYAML),
+	tomlFileExt:        decodeFunc(parseTOML),
+	csvFileExt:         csvDecoder(','),
+	tsvFileExt:         csvDecoder('\t'),
+	poFileExt:          decodeFunc(parsePO),
+	potFileExt:         decodeFunc(parsePOT),
+	xlfFileExt:         decodeFunc(parseXLIFF),
+	xliffFileExt:       decodeFunc(parseXLIFF),
+	arbFileExt:         arbDecoder{},
+	propertiesFileExt:  propertiesDecoder{},
+	iniFileExt:         decodeFunc(parseINI),
+	androidFileExt:     androidDecoder{},
+	stringsFileExt:     appleDecoder(parseAppleStrings),
+	stringsdictFileExt: appleDecoder(parseStringsDict),
+}}
+
+// RegisterDecoder makes d decode the source files whose extension is ext,
+// e.g. ".json", replacing the decoder of ext, if any. It is meant to be
+// called from init functions.
+func RegisterDecoder(ext string, d Decoder) {
+	if d == nil {
+		panic("localize: RegisterDecoder decoder is nil")
+	}
+	if len(ext) < 2 || ext[0] != '.' || path.Ext(ext) != ext {
+		panic("localize: RegisterDecoder extension " + ext + " is invalid")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.extensions[ext] = d
+}
+
+// RegisterSniffer makes d decode the source files that have no decoder for
+// their extension and whose first bytes, up to 512, are accepted by sniff.
+// Sniffers are tried in the order they were registered. It is meant to be
+// called from init functions.
+func RegisterSniffer(sniff func(head []byte) bool, d Decoder) {
+	if sniff == nil || d == nil {
+		panic("localize: RegisterSniffer sniff or decoder is nil")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.sniffers = append(registry.sniffers, sniffer{sniff: sniff, decoder: d})
+}
+
+// findDecoder returns the decoder of the file name, which starts with head,
+// or nil when its format is not supported.
+func findDecoder(name string, head []byte) Decoder {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if d := extensionDecoder(name); d != nil {
+		return d
+	}
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	for _, s := range registry.sniffers {
+		if s.sniff(head) {
+			return s.decoder
+		}
+	}
+	return nil
+}
+
+// extensionDecoder returns the decoder registered for the extension of name,
+// if it matches name. The registry must be locked.
+func extensionDecoder(name string) Decoder {
+	d, ok := registry.extensions[path.Ext(name)]
+	if !ok {
+		return nil
+	}
+	if m, ok := d.(Matcher); ok && !m.Match(name) {
+		return nil
+	}
+	return d
+}
+
+// registered reports whether ext has a decoder, which may not match all of
+// its files.
+func registered(ext string) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+	_, ok := registry.extensions[ext]
+	return ok
+}
+
+// supported reports whether the file name of fsys has a decoder, reading its
+// first bytes only when its extension has none and sniffers are registered.
+func supported(fsys fs.FS, name string) (bool, error) {
+	registry.RLock()
+	ok := extensionDecoder(name) != nil
+	sniff := len(registry.sniffers) > 0
+	registry.RUnlock()
+	if ok || !sniff {
+		return ok, nil
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return findDecoder(name, head[:n]) != nil, nil
+}
@@ -0,0 +1,155 @@
+package localize
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// restoreRegistry undoes the registrations of t when it ends.
+func restoreRegistry(t *testing.T) {
+	registry.Lock()
+	extensions := make(map[string]Decoder, len(registry.extensions))
+	for ext, d := range registry.extensions {
+		extensions[ext] = d
+	}
+	sniffers := append([]sniffer(nil), registry.sniffers...)
+	registry.Unlock()
+
+	t.Cleanup(func() {
+		registry.Lock()
+		registry.extensions = extensions
+		registry.sniffers = sniffers
+		registry.Unlock()
+	})
+}
+
+// parseLines decodes "key=value" lines, for the tests of the registry.
+func parseLines(value []byte, l *SourceFile, _ Options) error {
+	l.Localizations = map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(value)), "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			l.Localizations[parts[0]] = parts[1]
+		}
+	}
+	return nil
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	restoreRegistry(t)
+	RegisterDecoder(".lines", DecoderFunc(parseLines))
+
+	fsys := fstest.MapFS{
+		"messages/en.lines": {Data: []byte("hello=Hello\n")},
+		"messages/es.other": {Data: []byte("hello=Hola\n")},
+	}
+	got, err := Load(fsys, Options{Logger: log.New(ioutil.Discard, "", 0)})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"en.messages.hello": "Hello"}
+	if !reflect.DeepEqual(got.Localizations, want) {
+		t.Errorf("Load() got = %v, want %v", got.Localizations, want)
+	}
+}
+
+// bundleLines decodes "key=value" lines from files named like Java resource
+// bundles, e.g. messages_es.lines.
+type bundleLines struct{}
+
+func (bundleLines) Decode(data []byte, f *SourceFile, o Options) error {
+	return parseLines(data, f, o)
+}
+
+func (bundleLines) NameLocale(name string) (string, string, bool) {
+	return javaBundleLocale(name)
+}
+
+func TestRegisterDecoder_nameLocaler(t *testing.T) {
+	restoreRegistry(t)
+	RegisterDecoder(".lines", bundleLines{})
+
+	fsys := fstest.MapFS{
+		"messages.lines":    {Data: []byte("hello=Hello\n")},
+		"messages_es.lines": {Data: []byte("hello=Hola\n")},
+	}
+	got, err := Load(fsys, Options{Logger: log.New(ioutil.Discard, "", 0)})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"en.messages.hello": "Hello", "es.messages.hello": "Hola"}
+	if !reflect.DeepEqual(got.Localizations, want) {
+		t.Errorf("Load() got = %v, want %v", got.Localizations, want)
+	}
+}
+
+func TestRegisterDecoder_options(t *testing.T) {
+	restoreRegistry(t)
+	RegisterDecoder(".lines", DecoderFunc(func(data []byte, f *SourceFile, o Options) error {
+		f.Localizations = map[string]string{"delimiter": string(o.CSVDelimiter)}
+		return nil
+	}))
+
+	fsys := fstest.MapFS{"en.lines": {Data: []byte("")}}
+	got, err := Load(fsys, Options{CSVDelimiter: ';', Logger: log.New(ioutil.Discard, "", 0)})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"en.delimiter": ";"}
+	if !reflect.DeepEqual(got.Localizations, want) {
+		t.Errorf("Load() got = %v, want %v", got.Localizations, want)
+	}
+}
+
+func TestRegisterDecoder_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		d    Decoder
+	}{
+		{name: "no dot", ext: "lines", d: DecoderFunc(parseLines)},
+		{name: "dot only", ext: ".", d: DecoderFunc(parseLines)},
+		{name: "nil decoder", ext: ".lines"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreRegistry(t)
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterDecoder() did not panic")
+				}
+			}()
+			RegisterDecoder(tt.ext, tt.d)
+		})
+	}
+}
+
+func TestRegisterSniffer(t *testing.T) {
+	restoreRegistry(t)
+	RegisterSniffer(func(head []byte) bool {
+		return bytes.HasPrefix(head, []byte("#lines\n"))
+	}, DecoderFunc(parseLines))
+
+	fsys := fstest.MapFS{
+		"messages/en":      {Data: []byte("#lines\nhello=Hello\n")},
+		"messages/es":      {Data: []byte("hello=Hola\n")},
+		"messages/fr.json": {Data: []byte(`{"hello": "Bonjour"}`)},
+	}
+	logs := &bytes.Buffer{}
+	got, err := Load(fsys, Options{Logger: log.New(logs, "", 0)})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"en.messages.hello": "Hello", "fr.messages.hello": "Bonjour"}
+	if !reflect.DeepEqual(got.Localizations, want) {
+		t.Errorf("Load() got = %v, want %v", got.Localizations, want)
+	}
+	if !strings.Contains(logs.String(), "skipping messages/es") {
+		t.Errorf("Load() did not warn about messages/es, logs: %q", logs.String())
+	}
+}
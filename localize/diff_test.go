@@ -0,0 +1,73 @@
+package localize
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_unifiedDiff(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "equal",
+			args: args{a: "a\nb\n", b: "a\nb\n"},
+			want: "",
+		},
+		{
+			name: "changed line",
+			args: args{a: "1\n2\n3\n4\n5\n6\n7\n8\n", b: "1\n2\n3\n4\nfive\n6\n7\n8\n"},
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			args: args{a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", b: "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\neleven\n"},
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -8,3 +8,4 @@\n 8\n 9\n 10\n+eleven\n",
+		},
+		{
+			name: "new file",
+			args: args{a: "", b: "a\n"},
+			want: "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name: "no newline at end of file",
+			args: args{a: "a\nb", b: "a\nb\n"},
+			want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a", "b", []byte(tt.args.a), []byte(tt.args.b)); got != tt.want {
+				t.Errorf("unifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unifiedDiff_maxEdits(t *testing.T) {
+	a, b := &strings.Builder{}, &strings.Builder{}
+	a.WriteString("same\n")
+	b.WriteString("same\n")
+	for i := 0; i < diffMaxEdits; i++ {
+		fmt.Fprintf(a, "a%d\n", i)
+		fmt.Fprintf(b, "b%d\n", i)
+	}
+
+	got := unifiedDiff("a", "b", []byte(a.String()), []byte(b.String()))
+	lines := diffMaxEdits + 1
+	want := fmt.Sprintf("--- a\n+++ b\n@@ -1,%d +1,%d @@\n-same\n-a0\n", lines, lines)
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("unifiedDiff() starts with %q, want %q", got[:len(want)], want)
+	}
+	// The "+++ b" header also starts with a "+".
+	if n := strings.Count(got, "\n+") - 1; n != lines {
+		t.Errorf("unifiedDiff() adds %d lines, want %d", n, lines)
+	}
+}
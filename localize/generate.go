@@ -0,0 +1,350 @@
+package localize
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fitzix/go-localize/i18n"
+)
+
+const (
+	// DefaultOutput is the default of Options.Output.
+	DefaultOutput = "localizations"
+
+	// RuntimeImport makes the generated package import the i18n package.
+	RuntimeImport = "import"
+	// RuntimeStandalone writes a copy of the i18n package into the generated
+	// package, so it has no dependency on go-localize.
+	RuntimeStandalone = "standalone"
+
+	runtimeImportPath = "github.com/fitzix/go-localize/i18n"
+	runtimeFileName   = "i18n.go"
+)
+
+var (
+	errInputNotSet          = errors.New("the input file system must be set")
+	errRuntimeInvalid       = errors.New("the runtime must be either \"import\" or \"standalone\"")
+	errReportFormatInvalid  = errors.New("the report format must be either \"text\" or \"json\"")
+	errKeyCollisionsInvalid = errors.New("key collisions must be either \"error\" or \"suffix\"")
+	errCheckTimestamp       = errors.New("check cannot be used with timestamp, the generated code would never be up to date")
+)
+
+// Result is the outcome of Generate.
+type Result struct {
+	// Catalog holds the localizations read from Options.Input.
+	Catalog *Catalog
+	// Report holds the problems found in the localizations, without the
+	// missing keys unless Options.ReportMissing is set.
+	Report Report
+	// Files are the files of every generator, in the order of Options.Emit,
+	// written unless Options.Check is set.
+	Files []File
+}
+
+// File is a generated file.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Generate reads the source files of o.Input, reports their problems and
+// writes the files of the generators of o.Emit, or checks that they are up
+// to date when o.Check is set.
+func Generate(ctx context.Context, o Options) (*Result, error) {
+	o, err := generateOptions(o)
+	if err != nil {
+		return nil, err
+	}
+
+	cat, err := Load(o.Input, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateLocales(o, cat.Localizations); err != nil {
+		return nil, err
+	}
+
+	r := newReport(o.DefaultLocale, cat.Localizations)
+	if !o.ReportMissing {
+		r.Missing = nil
+	}
+	if o.ReportMissing || r.Count() > 0 {
+		if o.Report != nil {
+			if err := r.Write(o.Report, o.ReportFormat); err != nil {
+				return nil, err
+			}
+		}
+		if o.Strict && r.Count() > 0 {
+			return nil, fmt.Errorf("%d localizations differ from %v", r.Count(), o.DefaultLocale)
+		}
+	}
+
+	var files []File
+	for _, name := range o.Emit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		dir := o.Output
+		if output, ok := o.Outputs[name]; ok {
+			dir = output
+		}
+		rendered, err := findGenerator(name).Render(dir, cat, o)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", name, err)
+		}
+		files = append(files, rendered...)
+	}
+
+	if o.Check {
+		err = checkFiles(files)
+	} else {
+		err = writeFiles(ctx, files)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Catalog: cat, Report: r, Files: files}, nil
+}
+
+// generateOptions validates the Generate options of o and fills in the
+// defaults of unset values. Those of Load are left to Load.
+func generateOptions(o Options) (Options, error) {
+	if o.Input == nil {
+		return o, errInputNotSet
+	}
+	if o.Output == "" {
+		o.Output = DefaultOutput
+	}
+
+	if len(o.Emit) == 0 {
+		o.Emit = []string{GeneratorGo}
+	}
+	emitted := make(map[string]struct{}, len(o.Emit))
+	for _, name := range o.Emit {
+		if findGenerator(name) == nil {
+			return o, fmt.Errorf("no generator named %q", name)
+		}
+		if _, ok := emitted[name]; ok {
+			return o, fmt.Errorf("generator %q is emitted twice", name)
+		}
+		emitted[name] = struct{}{}
+	}
+
+	if o.Runtime == "" {
+		o.Runtime = RuntimeImport
+	}
+	if o.Runtime != RuntimeImport && o.Runtime != RuntimeStandalone {
+		return o, errRuntimeInvalid
+	}
+
+	if o.ReportFormat == "" {
+		o.ReportFormat = ReportFormatText
+	}
+	if o.ReportFormat != ReportFormatText && o.ReportFormat != ReportFormatJSON {
+		return o, errReportFormatInvalid
+	}
+
+	if o.KeyCollisions == "" {
+		o.KeyCollisions = KeyCollisionsError
+	}
+	if o.KeyCollisions != KeyCollisionsError && o.KeyCollisions != KeyCollisionsSuffix {
+		return o, errKeyCollisionsInvalid
+	}
+
+	if o.Check && o.Timestamp {
+		return o, errCheckTimestamp
+	}
+
+	if o.DefaultLocale == "" {
+		o.DefaultLocale = DefaultLocale
+	}
+	if o.FallbackLocale == "" {
+		o.FallbackLocale = o.DefaultLocale
+	}
+	return o, nil
+}
+
+// validateLocales checks that the default and fallback locales of o are
+// among the locales found in localizations.
+func validateLocales(o Options, localizations map[string]string) error {
+	locales := getLocales(localizations)
+	found := make(map[string]struct{}, len(locales))
+	for _, locale := range locales {
+		found[locale] = struct{}{}
+	}
+
+	if _, ok := found[o.FallbackLocale]; !ok {
+		return fmt.Errorf("fallback locale %q has no localization files, found: %v",
+			o.FallbackLocale, strings.Join(locales, ", "))
+	}
+	if _, ok := found[o.DefaultLocale]; !ok {
+		return fmt.Errorf("default locale %q has no localization files, found: %v",
+			o.DefaultLocale, strings.Join(locales, ", "))
+	}
+	return nil
+}
+
+// getLocales returns the sorted locales of localizations, which are keyed by
+// "<locale>.<key>".
+func getLocales(localizations map[string]string) []string {
+	localeMap := make(map[string]struct{})
+	for key := range localizations {
+		locale, _ := splitLocalizationKey(key)
+		localeMap[locale] = struct{}{}
+	}
+
+	locales := make([]string, 0, len(localeMap))
+	for locale := range localeMap {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func writeFiles(ctx context.Context, files []File) error {
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(file.Path, file.Content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFiles compares files with those on disk, returning an error holding a
+// unified diff when they are out of date.
+func checkFiles(files []File) error {
+	var diffs []string
+	for _, file := range files {
+		existing, err := ioutil.ReadFile(file.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if diff := unifiedDiff(file.Path, file.Path+" (generated)", existing, file.Content); diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("the generated files are out of date, run go-localize to regenerate them:\n%v", strings.Join(diffs, ""))
+	}
+	return nil
+}
+
+// renderPackage renders the gofmt-formatted files of the Go package in dir,
+// named after dir, without writing them. The output only depends on its
+// arguments, unless o.Timestamp asks for the generation time to be included.
+func renderPackage(dir string, cat *Catalog, o Options) ([]File, error) {
+	parent := dir
+	if strings.Contains(dir, string(filepath.Separator)) {
+		parent = filepath.Base(dir)
+	}
+	if !token.IsIdentifier(parent) {
+		return nil, fmt.Errorf("the output directory %v cannot name a Go package, %q is not an identifier", dir, parent)
+	}
+
+	keyMap, err := keyConstants(cat.Keys, o.Aliases, o.KeyCollisions)
+	if err != nil {
+		return nil, err
+	}
+
+	values := tmplValues{
+		Hash:           hashLocalizations(cat.Localizations),
+		Keys:           keyMap,
+		Descriptions:   cat.Descriptions,
+		Placeholders:   cat.Placeholders,
+		Localizations:  cat.Localizations,
+		Package:        parent,
+		RuntimeImport:  runtimeImportPath,
+		Runtime:        "i18n.",
+		DefaultLocale:  o.DefaultLocale,
+		FallbackLocale: o.FallbackLocale,
+	}
+	if o.Timestamp {
+		values.Timestamp = time.Now().UTC()
+	}
+
+	var files []File
+	if o.Runtime == RuntimeStandalone {
+		values.RuntimeImport = ""
+		values.Runtime = ""
+		content, err := renderRuntimeFile(parent)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Join(dir, runtimeFileName), Content: content})
+	}
+
+	b := &bytes.Buffer{}
+	if err := packageTemplate.Execute(b, values); err != nil {
+		return nil, err
+	}
+	content, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, File{Path: filepath.Join(dir, parent+".go"), Content: content})
+
+	return files, nil
+}
+
+// hashLocalizations returns a hash of localizations that identifies the
+// generated code in place of a timestamp.
+func hashLocalizations(localizations map[string]string) string {
+	keys := make([]string, 0, len(localizations))
+	for key := range localizations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%q:%q\n", key, localizations[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// renderRuntimeFile renders a copy of the i18n package, renamed to package
+// pkg.
+func renderRuntimeFile(pkg string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, runtimeFileName, i18n.Source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop the package documentation, it describes the i18n package.
+	for i, group := range file.Comments {
+		if group == file.Doc {
+			file.Comments = append(file.Comments[:i], file.Comments[i+1:]...)
+			break
+		}
+	}
+	file.Doc = nil
+	file.Name.Name = pkg
+
+	b := &bytes.Buffer{}
+	b.WriteString("// Code generated by go-localize; DO NOT EDIT.\n\n")
+	if err := format.Node(b, fset, file); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
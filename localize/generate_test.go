@@ -0,0 +1,310 @@
+package localize
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGenerate(t *testing.T) {
+	dirValid := os.DirFS("../examples/localizations_src")
+	dirTestFiles := filepath.Join(t.TempDir(), "test_files")
+	tests := []struct {
+		name    string
+		o       Options
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			o:    Options{Input: dirValid, Output: dirTestFiles},
+		},
+		{
+			name: "valid standalone",
+			o:    Options{Input: dirValid, Output: dirTestFiles, Runtime: RuntimeStandalone},
+		},
+		{
+			name: "valid locales",
+			o:    Options{Input: dirValid, Output: dirTestFiles, DefaultLocale: "es", FallbackLocale: "en"},
+		},
+		{
+			name: "report missing",
+			o:    Options{Input: dirValid, Output: dirTestFiles, ReportMissing: true, Report: ioutil.Discard},
+		},
+		{
+			name:    "report missing strict",
+			o:       Options{Input: dirValid, Output: dirTestFiles, ReportMissing: true, Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "unknown generator",
+			o:       Options{Input: dirValid, Output: dirTestFiles, Emit: []string{"xml"}},
+			wantErr: true,
+		},
+		{
+			name:    "generator emitted twice",
+			o:       Options{Input: dirValid, Output: dirTestFiles, Emit: []string{GeneratorGo, GeneratorGo}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid runtime",
+			o:       Options{Input: dirValid, Output: dirTestFiles, Runtime: "vendored"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid report format",
+			o:       Options{Input: dirValid, Output: dirTestFiles, ReportFormat: "xml"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key collisions",
+			o:       Options{Input: dirValid, Output: dirTestFiles, KeyCollisions: "rename"},
+			wantErr: true,
+		},
+		{
+			name:    "check with timestamp",
+			o:       Options{Input: dirValid, Output: dirTestFiles, Check: true, Timestamp: true},
+			wantErr: true,
+		},
+		{
+			name:    "output not a package name",
+			o:       Options{Input: dirValid, Output: filepath.Join(t.TempDir(), "out-po")},
+			wantErr: true,
+		},
+		{
+			name:    "fallback locale without files",
+			o:       Options{Input: dirValid, Output: dirTestFiles, FallbackLocale: "fr"},
+			wantErr: true,
+		},
+		{
+			name:    "input not set",
+			o:       Options{Output: dirTestFiles},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			o:       Options{Input: fstest.MapFS{"en.json": {Data: []byte(`{"1st": "one"}`)}}, Output: dirTestFiles},
+			wantErr: true,
+		},
+		{
+			name:    "not valid",
+			o:       Options{Input: os.DirFS("mock"), Output: dirTestFiles},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.o.Logger = log.New(ioutil.Discard, "", 0)
+			got, err := Generate(context.Background(), tt.o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, file := range got.Files {
+				written, err := ioutil.ReadFile(file.Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(written, file.Content) {
+					t.Errorf("%v differs from Result.Files", file.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	o := Options{
+		Input:  fstest.MapFS{"en.json": {Data: []byte(`{"hello": "hello"}`)}},
+		Output: filepath.Join(t.TempDir(), "test_files"),
+	}
+	if _, err := Generate(ctx, o); err != context.Canceled {
+		t.Errorf("Generate() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := os.Stat(o.Output); !os.IsNotExist(err) {
+		t.Errorf("Generate() wrote %v", o.Output)
+	}
+}
+
+func TestGenerate_literals(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "escaping")
+	res, err := Generate(context.Background(), Options{Input: os.DirFS("mock/escaping"), Output: output})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"en.messages.double_quote": `She said "hello"`,
+		"en.messages.backslash":    `C:\Users\{{.name}}`,
+		"en.messages.backtick":     "Run `go generate` first",
+		"en.messages.multi_line":   "First line\nSecond line with \"quotes\"\n",
+		"en.messages.folded":       "folded text\n",
+		"en.messages.non_bmp":      "Party 🎉 𝄞 𠜎",
+		"en.messages.code":         `" + os.Getenv("HOME") + "`,
+		`en.messages.quoted "key"`: "value",
+	}
+	if !reflect.DeepEqual(res.Catalog.Localizations, want) {
+		t.Fatalf("Generate() localizations = %v, want %v", res.Catalog.Localizations, want)
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(output, "escaping.go"), nil, 0)
+	if err != nil {
+		t.Fatalf("generated file does not parse: %v", err)
+	}
+
+	got := map[string]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, err := strconv.Unquote(kv.Key.(*ast.BasicLit).Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := strconv.Unquote(kv.Value.(*ast.BasicLit).Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[key] = value
+		return false
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("generated localizations = %v, want %v", got, want)
+	}
+}
+
+func Test_validateLocales(t *testing.T) {
+	localizations := map[string]string{
+		"en.messages.hello": "hello",
+		"es.messages.hello": "hola",
+	}
+	tests := []struct {
+		name    string
+		o       Options
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			o:    Options{DefaultLocale: "es", FallbackLocale: "en"},
+		},
+		{
+			name:    "default locale not found",
+			o:       Options{DefaultLocale: "fr", FallbackLocale: "en"},
+			wantErr: true,
+		},
+		{
+			name:    "fallback locale not found",
+			o:       Options{DefaultLocale: "en", FallbackLocale: "fr"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateLocales(tt.o, localizations); (err != nil) != tt.wantErr {
+				t.Errorf("validateLocales() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_renderPackage(t *testing.T) {
+	localizations := map[string]string{
+		"en.messages.hello": "hello",
+		"es.messages.hello": "hola",
+		"en.messages.bye":   "bye",
+	}
+	cat := &Catalog{
+		Localizations: localizations,
+		Keys:          []string{"messages.bye", "messages.hello"},
+		Descriptions:  map[string]string{"messages.hello": "Greets the user.\nShown on the home page."},
+		Placeholders:  map[string]map[string]string{"messages.hello": {"name": "String"}},
+	}
+	tests := []struct {
+		name string
+		o    Options
+	}{
+		{
+			name: "import",
+			o:    Options{Output: "localizations", Runtime: RuntimeImport, DefaultLocale: "en", FallbackLocale: "en"},
+		},
+		{
+			name: "standalone",
+			o:    Options{Output: "localizations", Runtime: RuntimeStandalone, DefaultLocale: "en", FallbackLocale: "en"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderPackage(tt.o.Output, cat, tt.o)
+			if err != nil {
+				t.Fatalf("renderPackage() error = %v", err)
+			}
+			again, err := renderPackage(tt.o.Output, cat, tt.o)
+			if err != nil {
+				t.Fatalf("renderPackage() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, again) {
+				t.Errorf("renderPackage() is not deterministic")
+			}
+			description := "\t// Greets the user.\n\t// Shown on the home page.\n\t// Placeholders: {{.name}} string\n\tMessagesHello "
+			if main := got[len(got)-1]; !bytes.Contains(main.Content, []byte(description)) {
+				t.Errorf("%v does not document MessagesHello:\n%s", main.Path, main.Content)
+			}
+			for _, file := range got {
+				formatted, err := format.Source(file.Content)
+				if err != nil {
+					t.Fatalf("%v: %v", file.Path, err)
+				}
+				if !bytes.Equal(formatted, file.Content) {
+					t.Errorf("%v is not gofmt-formatted", file.Path)
+				}
+			}
+		})
+	}
+}
+
+func Test_checkFiles(t *testing.T) {
+	localizations := map[string]string{"en.messages.hello": "hello"}
+	cat := &Catalog{Localizations: localizations, Keys: []string{"messages.hello"}}
+	o := Options{Output: filepath.Join(t.TempDir(), "test_files"), Runtime: RuntimeStandalone, DefaultLocale: "en", FallbackLocale: "en"}
+
+	files, err := renderPackage(o.Output, cat, o)
+	if err != nil {
+		t.Fatalf("renderPackage() error = %v", err)
+	}
+	if err := checkFiles(files); err == nil {
+		t.Errorf("checkFiles() without generated files, want error")
+	}
+
+	if err := writeFiles(context.Background(), files); err != nil {
+		t.Fatalf("writeFiles() error = %v", err)
+	}
+	if err := checkFiles(files); err != nil {
+		t.Errorf("checkFiles() after writeFiles() error = %v", err)
+	}
+
+	localizations["en.messages.hello"] = "hello!"
+	files, err = renderPackage(o.Output, cat, o)
+	if err != nil {
+		t.Fatalf("renderPackage() error = %v", err)
+	}
+	if err := checkFiles(files); err == nil {
+		t.Errorf("checkFiles() with changed localizations, want error")
+	}
+}
@@ -0,0 +1,55 @@
+package localize
+
+import "sync"
+
+// GeneratorGo is the name of the generator of the Go package, the default of
+// Options.Emit.
+const GeneratorGo = "go"
+
+// Generator renders the output files of a catalog, such as the Go package.
+type Generator interface {
+	// Render returns the files generated from cat into dir, without writing
+	// them. The output should only depend on its arguments, so that
+	// Options.Check can compare it with the files on disk.
+	Render(dir string, cat *Catalog, o Options) ([]File, error)
+}
+
+// GeneratorFunc is a function used as a Generator.
+type GeneratorFunc func(dir string, cat *Catalog, o Options) ([]File, error)
+
+// Render calls fn(dir, cat, o).
+func (fn GeneratorFunc) Render(dir string, cat *Catalog, o Options) ([]File, error) {
+	return fn(dir, cat, o)
+}
+
+// generators holds the generators Options.Emit can name.
+var generators = struct {
+	sync.RWMutex
+	names map[string]Generator
+}{names: map[string]Generator{
+	GeneratorGo:   GeneratorFunc(renderPackage),
+	GeneratorJSON: GeneratorFunc(renderJSONBundles),
+	GeneratorTS:   GeneratorFunc(renderTypeScript),
+}}
+
+// RegisterGenerator makes g available to Options.Emit as name, replacing the
+// generator of name, if any. It is meant to be called from init functions.
+func RegisterGenerator(name string, g Generator) {
+	if g == nil {
+		panic("localize: RegisterGenerator generator is nil")
+	}
+	if name == "" {
+		panic("localize: RegisterGenerator name is empty")
+	}
+
+	generators.Lock()
+	defer generators.Unlock()
+	generators.names[name] = g
+}
+
+// findGenerator returns the generator registered as name, or nil.
+func findGenerator(name string) Generator {
+	generators.RLock()
+	defer generators.RUnlock()
+	return generators.names[name]
+}
@@ -0,0 +1,76 @@
+package localize
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// restoreGenerators undoes the registrations of t when it ends.
+func restoreGenerators(t *testing.T) {
+	generators.Lock()
+	names := make(map[string]Generator, len(generators.names))
+	for name, g := range generators.names {
+		names[name] = g
+	}
+	generators.Unlock()
+
+	t.Cleanup(func() {
+		generators.Lock()
+		generators.names = names
+		generators.Unlock()
+	})
+}
+
+// renderKeys renders the keys of a catalog, one per line, for the tests of
+// the registry.
+func renderKeys(dir string, cat *Catalog, o Options) ([]File, error) {
+	content := strings.Join(cat.Keys, "\n") + "\n"
+	return []File{{Path: filepath.Join(dir, "keys.txt"), Content: []byte(content)}}, nil
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	restoreGenerators(t)
+	RegisterGenerator("keys", GeneratorFunc(renderKeys))
+
+	output := filepath.Join(t.TempDir(), "localizations")
+	keysOutput := filepath.Join(t.TempDir(), "web")
+	o := Options{
+		Input:   fstest.MapFS{"messages/en.json": {Data: []byte(`{"hello": "Hello", "bye": "Bye"}`)}},
+		Output:  output,
+		Emit:    []string{GeneratorGo, "keys"},
+		Outputs: map[string]string{"keys": keysOutput},
+		Logger:  log.New(ioutil.Discard, "", 0),
+	}
+	res, err := Generate(context.Background(), o)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var paths []string
+	for _, file := range res.Files {
+		paths = append(paths, file.Path)
+	}
+	want := []string{filepath.Join(output, "localizations.go"), filepath.Join(keysOutput, "keys.txt")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Generate() files = %v, want %v", paths, want)
+	}
+
+	keys, err := ioutil.ReadFile(filepath.Join(keysOutput, "keys.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(keys); got != "messages.bye\nmessages.hello\n" {
+		t.Errorf("keys.txt = %q", got)
+	}
+
+	o.Check = true
+	if _, err := Generate(context.Background(), o); err != nil {
+		t.Errorf("Generate() with Check after generating error = %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package localize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const iniFileExt = ".ini"
+
+// parseINI decodes an INI file. The keys of a section are prefixed by its
+// name and a dot, e.g. "checkout.pay" for pay in [checkout]. Values may be
+// double quoted, in which case their escapes are resolved.
+func parseINI(value []byte, l *SourceFile) error {
+	l.Localizations = map[string]string{}
+
+	section := ""
+	lines := strings.Split(strings.ReplaceAll(string(value), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		n := i + 1
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "", line[0] == ';', line[0] == '#':
+			continue
+		case line[0] == '[':
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("line %d: unterminated section %v", n, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("line %d: expected key = value", n)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if key == "" {
+			return fmt.Errorf("line %d: empty key", n)
+		}
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("line %d: %v", n, err)
+			}
+			value = unquoted
+		}
+
+		l.Localizations[joinKey(section, key)] = value
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package localize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseINI(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "sections",
+			value: "global = 1\n[a]\nx = 2\n# comment\n[a.b]\ny = has = sign\n",
+			want:  map[string]string{"global": "1", "a.x": "2", "a.b.y": "has = sign"},
+		},
+		{
+			name:  "quoted value",
+			value: "[s]\nq = \"  spaced\\n\"\n",
+			want:  map[string]string{"s.q": "  spaced\n"},
+		},
+		{
+			name:    "unterminated section",
+			value:   "[a\nx = 1\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			value:   "[a]\nx\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid quoted value",
+			value:   "x = \"open\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseINI([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseINI() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Localizations, tt.want) {
+				t.Errorf("parseINI() got = %v, want %v", got.Localizations, tt.want)
+			}
+		})
+	}
+}
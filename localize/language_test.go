@@ -0,0 +1,26 @@
+package localize
+
+import "testing"
+
+func Test_isLanguageCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "es", want: true},
+		{code: "iw", want: true},
+		{code: "fil", want: true},
+		{code: "ui", want: false},
+		{code: "msg", want: false},
+		{code: "map", want: false},
+		{code: "und", want: false},
+		{code: "ES", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := isLanguageCode(tt.code); got != tt.want {
+				t.Errorf("isLanguageCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,556 @@
+// Package localize reads localization source files, in any of the supported
+// formats, merges them into a catalog and generates the Go package of the
+// catalog, as the go-localize command does.
+package localize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultLocale is the default of Options.DefaultLocale.
+	DefaultLocale = "en"
+
+	// DuplicatesError fails Load when several files define the same
+	// localization.
+	DuplicatesError = "error"
+	// DuplicatesFirst keeps the localization of the first file read.
+	DuplicatesFirst = "first"
+	// DuplicatesLast keeps the localization of the last file read.
+	DuplicatesLast = "last"
+)
+
+var (
+	errDuplicatesInvalid   = errors.New("duplicates must be either \"error\", \"first\" or \"last\"")
+	errCSVDelimiterInvalid = errors.New("the CSV delimiter cannot be a quote, a newline or an invalid character")
+)
+
+// Options configures Load and Generate. The fields after Logger are only used
+// by Generate.
+type Options struct {
+	// DefaultLocale is the locale of the files of the base directory or
+	// bundle of platforms with locale directories or file names, such as
+	// Android's values, that do not declare their own. Defaults to
+	// DefaultLocale.
+	DefaultLocale string
+	// Duplicates is what to do when several files define the same
+	// localization: DuplicatesError, the default, DuplicatesFirst or
+	// DuplicatesLast. Files are read in lexical order.
+	Duplicates string
+	// CSVDelimiter, when set, delimits CSV and TSV files instead of a comma
+	// and a tab.
+	CSVDelimiter rune
+	// Logger receives the warnings, such as skipped files. Defaults to the
+	// standard logger.
+	Logger *log.Logger
+
+	// Input holds the source files read by Generate.
+	Input fs.FS
+	// Output is the directory generators write to, unless set in Outputs.
+	// The Go package is named after its directory. Defaults to
+	// "localizations".
+	Output string
+	// Emit names the generators to run, such as GeneratorGo, the default.
+	Emit []string
+	// Outputs overrides Output for the generators it names.
+	Outputs map[string]string
+	// Runtime is how the generated package gets its runtime: RuntimeImport,
+	// the default, or RuntimeStandalone.
+	Runtime string
+	// FallbackLocale is the locale used when a key has no translation.
+	// Defaults to DefaultLocale.
+	FallbackLocale string
+	// Timestamp includes the generation time in the generated code, which
+	// otherwise only changes when the localizations do.
+	Timestamp bool
+	// Check compares the generated package with the files in Output instead
+	// of writing it, failing with a unified diff when they are out of date.
+	Check bool
+	// ReportMissing also reports the keys each locale is missing compared to
+	// the default locale.
+	ReportMissing bool
+	// ReportFormat is the format of the report: ReportFormatText, the
+	// default, or ReportFormatJSON.
+	ReportFormat string
+	// Report receives the report, when it is not empty or ReportMissing is
+	// set. Reports are not written when it is nil.
+	Report io.Writer
+	// Strict fails Generate when the report is not empty.
+	Strict bool
+	// Aliases maps keys to the names of their generated constants.
+	Aliases map[string]string
+	// KeyCollisions is what to do when keys generate the same constant:
+	// KeyCollisionsError, the default, or KeyCollisionsSuffix.
+	KeyCollisions string
+	// JSONNested nests the keys of the JSON bundles by their dotted parts
+	// instead of keeping them flat.
+	JSONNested bool
+	// JSONMinify writes the JSON bundles without indentation.
+	JSONMinify bool
+	// TSDeclaration writes the TypeScript definitions to a .d.ts file
+	// instead of a .ts file.
+	TSDeclaration bool
+}
+
+// Load reads the source files of fsys, in lexical order, and merges their
+// localizations. Archives are read in memory, and their files appear under
+// the path of their archive. Files of unsupported formats are skipped with
+// a warning.
+func Load(fsys fs.FS, o Options) (*Catalog, error) {
+	if o.DefaultLocale == "" {
+		o.DefaultLocale = DefaultLocale
+	}
+	if o.Duplicates == "" {
+		o.Duplicates = DuplicatesError
+	}
+	if o.Duplicates != DuplicatesError && o.Duplicates != DuplicatesFirst && o.Duplicates != DuplicatesLast {
+		return nil, errDuplicatesInvalid
+	}
+	if o.CSVDelimiter == '"' || o.CSVDelimiter == '\r' || o.CSVDelimiter == '\n' || o.CSVDelimiter == utf8.RuneError {
+		return nil, errCSVDelimiterInvalid
+	}
+	if o.Logger == nil {
+		o.Logger = log.Default()
+	}
+
+	input := newInputFS(fsys)
+	files, err := getLocalizationFiles(o, input)
+	if err != nil {
+		return nil, err
+	}
+	cat, err := generateLocalizations(o, input, files)
+	if err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+const (
+	jsonFileExt = ".json"
+	yamlFileExt = ".yaml"
+	ymlFileExt  = ".yml"
+	tomlFileExt = ".toml"
+	csvFileExt  = ".csv"
+	zipFileExt  = ".zip"
+)
+
+// Catalog is the merged content of the source files.
+type Catalog struct {
+	// Localizations maps "<locale>.<key>" to its value.
+	Localizations map[string]string
+	// Keys are the sorted keys, without their locale.
+	Keys []string
+	// Descriptions documents keys, e.g. from the notes of XLIFF files.
+	Descriptions map[string]string
+	// Placeholders maps keys to the types of their placeholders, by name.
+	Placeholders map[string]map[string]string
+}
+
+// generateLocalizations merges the localizations of files, read from fsys.
+// When several files define the same localization, o.Duplicates decides
+// whether that is an error or whether the first or last file read wins.
+func generateLocalizations(o Options, fsys fs.FS, files []string) (Catalog, error) {
+	localizations := map[string]string{}
+	descriptions := map[string]string{}
+	placeholders := map[string]map[string]string{}
+	sources := map[string]string{}
+	keyMap := make(map[string]struct{})
+	for _, file := range files {
+		fileCatalog, err := getLocalizationsFromFile(o, fsys, file)
+		if err != nil {
+			return Catalog{}, err
+		}
+		newLocalizations := fileCatalog.Localizations
+
+		newKeys := make([]string, 0, len(newLocalizations))
+		for key := range newLocalizations {
+			newKeys = append(newKeys, key)
+		}
+		sort.Strings(newKeys)
+
+		for _, key := range newKeys {
+			if source, ok := sources[key]; ok {
+				switch o.Duplicates {
+				case DuplicatesFirst:
+					o.Logger.Printf("%v: ignoring %q, already defined in %v", file, key, source)
+					continue
+				case DuplicatesLast:
+					o.Logger.Printf("%v: %q overrides the one defined in %v", file, key, source)
+				default:
+					return Catalog{}, fmt.Errorf("%q is defined in both %v and %v", key, source, file)
+				}
+			}
+			localizations[key] = newLocalizations[key]
+			sources[key] = file
+		}
+
+		for _, v := range fileCatalog.Keys {
+			keyMap[v] = struct{}{}
+		}
+
+		// Like the localizations, the documentation of keys comes from the
+		// last file read with DuplicatesLast, and from the first otherwise.
+		for key, description := range fileCatalog.Descriptions {
+			if _, ok := descriptions[key]; !ok || o.Duplicates == DuplicatesLast {
+				descriptions[key] = description
+			}
+		}
+		for key, types := range fileCatalog.Placeholders {
+			if _, ok := placeholders[key]; !ok || o.Duplicates == DuplicatesLast {
+				placeholders[key] = types
+			}
+		}
+	}
+
+	keys := make([]string, 0)
+
+	for k := range keyMap {
+		keys = append(keys, k)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	return Catalog{
+		Localizations: localizations,
+		Keys:          keys,
+		Descriptions:  descriptions,
+		Placeholders:  placeholders,
+	}, nil
+}
+
+// getLocalizationFiles returns the paths of the source files of fsys, in
+// lexical order. The files of archives are included, under the path of their
+// archive.
+func getLocalizationFiles(o Options, fsys *inputFS) ([]string, error) {
+	var files []string
+	add := func(name string) error {
+		ok, err := supported(fsys, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if registered(path.Ext(name)) {
+				o.Logger.Printf("skipping %v: not read by the decoder of %q files", name, path.Ext(name))
+			} else {
+				o.Logger.Printf("skipping %v: unsupported file format, extension %q", name, path.Ext(name))
+			}
+			return nil
+		}
+		files = append(files, name)
+		return nil
+	}
+
+	err := fs.WalkDir(fsys.FS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !isArchive(name) {
+			return add(name)
+		}
+
+		archived, err := fsys.mount(name)
+		if err != nil {
+			return err
+		}
+		for _, name := range archived {
+			if err := add(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// getLocalizationsFromFile reads the localizations of file from fsys. Files
+// in the base directory or bundle of a DirLocaler or NameLocaler format, such
+// as Android's values, belong to o.DefaultLocale unless they declare their
+// own.
+func getLocalizationsFromFile(o Options, fsys fs.FS, file string) (Catalog, error) {
+	byteValue, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	decoder := findDecoder(file, byteValue)
+	if decoder == nil {
+		return Catalog{}, nil
+	}
+
+	sourceFile := SourceFile{}
+	if err := decoder.Decode(byteValue, &sourceFile, o); err != nil {
+		return Catalog{}, fmt.Errorf("%v: %v", file, err)
+	}
+	for _, warning := range sourceFile.Warnings {
+		o.Logger.Printf("%v: %v", file, warning)
+	}
+
+	slicePath := getSlicePath(file)
+	locale := sourceFile.Locale
+	if localer, ok := decoder.(DirLocaler); ok && len(slicePath) > 1 {
+		if dirLocale, ok := localer.DirLocale(slicePath[len(slicePath)-1]); ok {
+			slicePath = slicePath[:len(slicePath)-1]
+			if dirLocale != "" {
+				locale = dirLocale
+			} else if locale == "" {
+				locale = o.DefaultLocale
+			}
+		}
+	}
+	if localer, ok := decoder.(NameLocaler); ok {
+		if nameLocale, base, ok := localer.NameLocale(slicePath[0]); ok {
+			slicePath = append([]string{base}, slicePath[1:]...)
+			if nameLocale != "" {
+				locale = nameLocale
+			} else if locale == "" {
+				locale = o.DefaultLocale
+			}
+		}
+	}
+	// localizations holds the localizations of the file by locale, and
+	// keyPrefix the prefix of their keys.
+	localizations := sourceFile.Locales
+	var keyPrefix string
+	if len(localizations) > 0 {
+		// The file name is part of the key prefix of all its locales.
+		keyPrefix = strings.Join(append(append([]string{}, slicePath[1:]...), slicePath[0]), ".")
+	} else {
+		if locale != "" {
+			slicePath = withLocale(slicePath, locale)
+		}
+		if len(slicePath) > 1 {
+			keyPrefix = strings.Join(slicePath[1:], ".")
+		}
+		localizations = map[string]map[string]string{slicePath[0]: sourceFile.Localizations}
+	}
+
+	cat := Catalog{
+		Localizations: map[string]string{},
+		Keys:          make([]string, 0, len(sourceFile.Localizations)),
+		Descriptions:  map[string]string{},
+		Placeholders:  map[string]map[string]string{},
+	}
+	keyMap := make(map[string]struct{})
+	for locale, values := range localizations {
+		for key, value := range values {
+			if _, err := Placeholders(value); err != nil {
+				return Catalog{}, fmt.Errorf("%v: key %q: %v", file, key, err)
+			}
+			tmpKey := joinKey(keyPrefix, key)
+			cat.Localizations[locale+"."+tmpKey] = value
+			if _, ok := keyMap[tmpKey]; !ok {
+				keyMap[tmpKey] = struct{}{}
+				cat.Keys = append(cat.Keys, tmpKey)
+			}
+			if description := sourceFile.Descriptions[key]; description != "" {
+				cat.Descriptions[tmpKey] = description
+			}
+			if types, ok := sourceFile.Placeholders[key]; ok {
+				cat.Placeholders[tmpKey] = types
+			}
+		}
+	}
+	sort.Strings(cat.Keys)
+
+	return cat, nil
+}
+
+// parseJSON decodes a JSON document. Numbers are kept as written, e.g.
+// 1000000 rather than 1e+06.
+func parseJSON(value []byte, l *SourceFile) error {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after the top-level object")
+	}
+	l.Localizations = map[string]string{}
+	return flatten("", doc, l.Localizations)
+}
+
+// parseYAML decodes a YAML document. Scalars are kept as written, e.g. No
+// and 1.10 rather than false and 1.1, keys included.
+func parseYAML(value []byte, l *SourceFile) error {
+	var doc yamlLiteral
+	if err := yaml.Unmarshal(value, &doc); err != nil {
+		return err
+	}
+	m, ok := doc.value.(map[string]interface{})
+	if doc.value != nil && !ok {
+		return errors.New("the document is not a mapping")
+	}
+	l.Localizations = map[string]string{}
+	return flatten("", m, l.Localizations)
+}
+
+// yamlLiteral is a YAML node whose scalars are decoded as their literal
+// text. Its value is a map[string]interface{}, a []interface{}, a string or
+// nil for null.
+type yamlLiteral struct {
+	value interface{}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (v *yamlLiteral) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	switch raw.(type) {
+	case nil:
+		v.value = nil
+	case map[interface{}]interface{}:
+		var m map[string]yamlLiteral
+		if err := unmarshal(&m); err != nil {
+			return err
+		}
+		doc := make(map[string]interface{}, len(m))
+		for key, child := range m {
+			doc[key] = child.value
+		}
+		v.value = doc
+	case []interface{}:
+		var list []yamlLiteral
+		if err := unmarshal(&list); err != nil {
+			return err
+		}
+		values := make([]interface{}, len(list))
+		for i, child := range list {
+			values[i] = child.value
+		}
+		v.value = values
+	default:
+		var s string
+		if err := unmarshal(&s); err != nil {
+			return err
+		}
+		v.value = s
+	}
+	return nil
+}
+
+func parseTOML(value []byte, l *SourceFile) error {
+	var doc map[string]interface{}
+	if _, err := toml.Decode(string(value), &doc); err != nil {
+		return err
+	}
+	l.Localizations = map[string]string{}
+	return flatten("", doc, l.Localizations)
+}
+
+// flatten walks a decoded document and stores every leaf value in l, joining
+// the keys of nested maps and the indexes of lists with dots, so that
+// {"checkout": {"button": {"pay": "Pay"}}} becomes "checkout.button.pay".
+// Leaves that end up with the same key are an error.
+func flatten(prefix string, value interface{}, l map[string]string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if err := flatten(joinKey(prefix, key), child, l); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			if err := flatten(joinKey(prefix, fmt.Sprint(key)), child, l); err != nil {
+				return err
+			}
+		}
+	case []map[string]interface{}:
+		for i, child := range v {
+			if err := flatten(joinKey(prefix, strconv.Itoa(i)), child, l); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if err := flatten(joinKey(prefix, strconv.Itoa(i)), child, l); err != nil {
+				return err
+			}
+		}
+	default:
+		// A dotted key and nested maps can both lead to the same key, e.g.
+		// "a.b" and {"a": {"b": ...}}, and map order would pick the winner.
+		if _, ok := l[prefix]; ok {
+			return fmt.Errorf("key %q is defined both as a dotted key and by nesting", prefix)
+		}
+		switch v := v.(type) {
+		case nil:
+			l[prefix] = ""
+		case string:
+			l[prefix] = v
+		case json.Number:
+			l[prefix] = v.String()
+		default:
+			l[prefix] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// withLocale replaces the locale of slicePath, as returned by getSlicePath,
+// with locale. The file name, which getSlicePath takes as the locale, becomes
+// the last part of the key prefix unless it is the locale itself.
+func withLocale(slicePath []string, locale string) []string {
+	strs := append([]string{locale}, slicePath[1:]...)
+	if slicePath[0] != locale {
+		strs = append(strs, slicePath[0])
+	}
+	return strs
+}
+
+// getSlicePath returns the file name of file, which is relative to the
+// input, followed by its directories. The files of archives are keyed as if
+// they had been extracted next to their archive.
+func getSlicePath(file string) []string {
+	dir, file := path.Split(file)
+
+	var strs []string
+	for _, part := range strings.Split(dir, "/") {
+		part := strings.TrimSpace(part)
+		if part != "" && !isArchive(part) {
+			strs = append(strs, part)
+		}
+	}
+
+	strs = append(strs, strings.TrimSuffix(file, path.Ext(file)))
+
+	if length := len(strs); length > 1 {
+		keyPath := make([]string, 0, length)
+		keyPath = append(keyPath, strs[length-1])
+		keyPath = append(keyPath, strs[:length-1]...)
+		strs = keyPath
+	}
+
+	return strs
+}
@@ -0,0 +1,693 @@
+package localize
+
+import (
+	"embed"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_generateLocalizations(t *testing.T) {
+	type args struct {
+		dir        string
+		files      []string
+		duplicates string
+	}
+	duplicateFiles := []string{
+		"messages/en.json",
+		"messages/en.yaml",
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			args: args{
+				dir: ".",
+				files: []string{
+					"mock/dir/sub/valid_json.json",
+					"mock/dir/valid_json.json",
+					"mock/dir/valid_yaml.yaml",
+					"mock/dir/valid_csv.csv",
+					"mock/dir/valid_toml.toml",
+					"mock/dir/dont_parse.txt",
+				},
+			},
+			want: map[string]string{
+				"valid_json.mock.dir.sub.test": "test",
+				"valid_json.mock.dir.test":     "test",
+				"valid_yaml.mock.dir.test":     "test",
+				"valid_csv.mock.dir.test":      "test",
+				"valid_toml.mock.dir.test":     "test",
+			},
+		},
+		{
+			name:    "duplicates error",
+			args:    args{dir: "mock/duplicates", files: duplicateFiles, duplicates: DuplicatesError},
+			wantErr: true,
+		},
+		{
+			name: "duplicates first",
+			args: args{dir: "mock/duplicates", files: duplicateFiles, duplicates: DuplicatesFirst},
+			want: map[string]string{
+				"en.messages.hello": "hello from json",
+				"en.messages.bye":   "bye",
+			},
+		},
+		{
+			name: "duplicates last",
+			args: args{dir: "mock/duplicates", files: duplicateFiles, duplicates: DuplicatesLast},
+			want: map[string]string{
+				"en.messages.hello": "hello from yaml",
+				"en.messages.bye":   "bye",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := Options{Duplicates: tt.args.duplicates, Logger: log.Default()}
+			got, err := generateLocalizations(o, os.DirFS(tt.args.dir), tt.args.files)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("generateLocalizations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Localizations, tt.want) {
+				t.Errorf("generateLocalizations() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_generateLocalizations_metadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		"l10n/app.arb": {Data: []byte(`{"@@locale": "en", "hello": "Hello {name}",
+			"@hello": {"description": "from app.arb", "placeholders": {"name": {"type": "String"}}}}`)},
+		"l10n/app_en.arb": {Data: []byte(`{"hello": "Hi {name}",
+			"@hello": {"description": "from app_en.arb", "placeholders": {"name": {"type": "int"}}}}`)},
+	}
+	files := []string{"l10n/app.arb", "l10n/app_en.arb"}
+	tests := []struct {
+		name             string
+		duplicates       string
+		wantValue        string
+		wantDescription  string
+		wantPlaceholders map[string]string
+	}{
+		{
+			name:             "duplicates first",
+			duplicates:       DuplicatesFirst,
+			wantValue:        "Hello {{.name}}",
+			wantDescription:  "from app.arb",
+			wantPlaceholders: map[string]string{"name": "String"},
+		},
+		{
+			name:             "duplicates last",
+			duplicates:       DuplicatesLast,
+			wantValue:        "Hi {{.name}}",
+			wantDescription:  "from app_en.arb",
+			wantPlaceholders: map[string]string{"name": "int"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := Options{DefaultLocale: DefaultLocale, Duplicates: tt.duplicates, Logger: log.New(io.Discard, "", 0)}
+			got, err := generateLocalizations(o, fsys, files)
+			if err != nil {
+				t.Fatalf("generateLocalizations() error = %v", err)
+			}
+			if value := got.Localizations["en.l10n.app.hello"]; value != tt.wantValue {
+				t.Errorf("generateLocalizations() value = %q, want %q", value, tt.wantValue)
+			}
+			if description := got.Descriptions["l10n.app.hello"]; description != tt.wantDescription {
+				t.Errorf("generateLocalizations() description = %q, want %q", description, tt.wantDescription)
+			}
+			if placeholders := got.Placeholders["l10n.app.hello"]; !reflect.DeepEqual(placeholders, tt.wantPlaceholders) {
+				t.Errorf("generateLocalizations() placeholders = %v, want %v", placeholders, tt.wantPlaceholders)
+			}
+		})
+	}
+}
+
+func Test_getLocalizationsFromFile(t *testing.T) {
+	type args struct {
+		dir  string
+		file string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "valid json",
+			args: args{file: "mock/valid.json"},
+			want: map[string]string{"valid.mock.test1": "test2"},
+		},
+		{
+			name: "valid yaml",
+			args: args{file: "mock/valid.yaml"},
+			want: map[string]string{"valid.mock.test1": "test2"},
+		},
+		{
+			name: "nested json",
+			args: args{file: "mock/nested/nested.json"},
+			want: map[string]string{
+				"nested.mock.nested.checkout.button.pay": "Pay now",
+				"nested.mock.nested.checkout.items.0":    "one",
+				"nested.mock.nested.checkout.items.1":    "two",
+				"nested.mock.nested.title":               "Checkout",
+			},
+		},
+		{
+			name: "nested yaml",
+			args: args{file: "mock/nested/nested.yaml"},
+			want: map[string]string{
+				"nested.mock.nested.checkout.button.pay": "Pay now",
+				"nested.mock.nested.checkout.total":      "3",
+				"nested.mock.nested.title":               "Checkout",
+			},
+		},
+		{
+			name: "nested toml",
+			args: args{file: "mock/nested/nested.toml"},
+			want: map[string]string{
+				"nested.mock.nested.checkout.button.pay": "Pay now",
+				"nested.mock.nested.title":               "Checkout",
+			},
+		},
+		{
+			name: "po with language header",
+			args: args{file: "mock/po/catalog.po"},
+			want: map[string]string{
+				"es.mock.po.catalog.hello":     "Hola",
+				"es.mock.po.catalog.menu.open": "Abrir",
+				"es.mock.po.catalog.apples.0":  "{{.count}} manzana",
+				"es.mock.po.catalog.apples.1":  "{{.count}} manzanas",
+				"es.mock.po.catalog.long":      "first line\nsecond \"line\"",
+			},
+		},
+		{
+			name: "xliff with target language",
+			args: args{file: "mock/xliff/messages.xlf"},
+			want: map[string]string{
+				"es.mock.xliff.messages.hello":           "Hola",
+				"es.mock.xliff.messages.checkout.pay":    "Pagar ahora",
+				"es.mock.xliff.messages.checkout.cancel": "Cancel",
+			},
+		},
+		{
+			name: "android resources",
+			args: args{dir: "mock/android", file: "values-es-rMX/strings.xml"},
+			want: map[string]string{
+				"es-MX.strings.app_name":     "Mi aplicación",
+				"es-MX.strings.apples.one":   "{{.count}} manzana",
+				"es-MX.strings.apples.other": "{{.count}} manzanas",
+				"es-MX.strings.planets.0":    "Mercurio",
+				"es-MX.strings.planets.1":    "Venus",
+			},
+		},
+		{
+			name: "android base resources",
+			args: args{dir: "mock/android", file: "values/strings.xml"},
+			want: map[string]string{
+				"en.strings.app_name": "My app",
+				"en.strings.welcome":  "Welcome, {{.name}}!\nIt's good to see you.",
+			},
+		},
+		{
+			name: "apple strings",
+			args: args{dir: "mock/apple", file: "es.lproj/Localizable.strings"},
+			want: map[string]string{
+				"es.Localizable.hello":    "Hola",
+				"es.Localizable.greeting": "Hola, \"{{.name}}\"",
+			},
+		},
+		{
+			name: "apple stringsdict",
+			args: args{dir: "mock/apple", file: "es.lproj/Localizable.stringsdict"},
+			want: map[string]string{
+				"es.Localizable.apples":             "%#@count@",
+				"es.Localizable.apples.count.one":   "{{.count}} manzana",
+				"es.Localizable.apples.count.other": "{{.count}} manzanas",
+			},
+		},
+		{
+			name: "arb with locale",
+			args: args{dir: "mock/arb", file: "app_fr.arb"},
+			want: map[string]string{
+				"fr.app.hello":   "Bonjour {{.name}}",
+				"fr.app.goodbye": "Au revoir",
+			},
+		},
+		{
+			name: "arb with locale suffix",
+			args: args{dir: "mock/arb", file: "app_de.arb"},
+			want: map[string]string{"de.app.hello": "Hallo"},
+		},
+		{
+			name: "properties with locale suffix",
+			args: args{dir: "mock/properties", file: "messages_es.properties"},
+			want: map[string]string{
+				"es.messages.hello":   "Hola",
+				"es.messages.welcome": "Bienvenido, {{.name}}",
+				"es.messages.café":    "Café",
+			},
+		},
+		{
+			name: "properties base bundle",
+			args: args{dir: "mock/properties", file: "messages.properties"},
+			want: map[string]string{"en.messages.hello": "Hello"},
+		},
+		{
+			name: "ini sections",
+			args: args{dir: "mock/ini", file: "en.ini"},
+			want: map[string]string{
+				"en.title":           "Checkout",
+				"en.checkout.pay":    "Pay now",
+				"en.checkout.cancel": `Cancel "order"`,
+			},
+		},
+		{
+			name: "csv with a column per locale",
+			args: args{dir: "mock/csv", file: "messages.csv"},
+			want: map[string]string{
+				"en.messages.hello": "Hello",
+				"en.messages.bye":   "Bye",
+				"es.messages.hello": "Hola",
+			},
+		},
+		{
+			name: "tsv with a column per locale",
+			args: args{dir: "mock/csv", file: "errors.tsv"},
+			want: map[string]string{
+				"en.errors.not_found": "Not found, sorry",
+				"fr.errors.not_found": "Introuvable",
+			},
+		},
+		{
+			name:    "file not exist",
+			args:    args{file: "mock/non_exist.json"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			args:    args{file: "mock/invalid.json"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			args:    args{file: "mock/invalid_template.yaml"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tt.args.dir
+			if dir == "" {
+				dir = "."
+			}
+			got, err := getLocalizationsFromFile(Options{DefaultLocale: DefaultLocale, Logger: log.Default()}, os.DirFS(dir), tt.args.file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getLocalizationsFromFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Localizations, tt.want) {
+				t.Errorf("getLocalizationsFromFile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getSlicePath(t *testing.T) {
+	type args struct {
+		file string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "valid",
+			args: args{"mock/valid.json"},
+			want: []string{"valid", "mock"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getSlicePath(tt.args.file); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getSlicePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getLocalizationFiles(t *testing.T) {
+	type args struct {
+		dir string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			args: args{"mock/dir"},
+			want: []string{
+				"sub/valid_json.json",
+				"valid_csv.csv",
+				"valid_json.json",
+				"valid_toml.toml",
+				"valid_yaml.yaml",
+			},
+		},
+		{
+			name:    "missing dir",
+			args:    args{"mock/non_exist"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getLocalizationFiles(Options{Logger: log.Default()}, newInputFS(os.DirFS(tt.args.dir)))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getLocalizationFiles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getLocalizationFiles() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_csvDecoder_Decode(t *testing.T) {
+	type args struct {
+		value []byte
+		l     *SourceFile
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		want    *SourceFile
+	}{
+		{
+			name: "valid",
+			args: args{
+				value: []byte("test,test"),
+				l:     &SourceFile{},
+			},
+			want: &SourceFile{Localizations: map[string]string{
+				"test": "test",
+			}},
+		},
+		{
+			name: "not valid",
+			args: args{
+				value: []byte("test,test\ntest,test,test"),
+				l:     &SourceFile{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "record length above 2",
+			args: args{
+				value: []byte("test,test,test"),
+				l:     &SourceFile{},
+			},
+			want: &SourceFile{Localizations: map[string]string{"test": "test"}},
+		},
+		{
+			name: "key and value header",
+			args: args{
+				value: []byte("\xEF\xBB\xBFkey,value\nhello,Hello\n"),
+				l:     &SourceFile{},
+			},
+			want: &SourceFile{Localizations: map[string]string{"hello": "Hello"}},
+		},
+		{
+			name: "column per locale",
+			args: args{
+				value: []byte("\xEF\xBB\xBFkey,en,es,description\n" +
+					"hello,Hello,Hola,Greets the user\n" +
+					"bye,Bye,,\n" +
+					"multi,\"Two\nlines\",\"Dos\nlíneas\",\n"),
+				l: &SourceFile{},
+			},
+			want: &SourceFile{
+				Locales: map[string]map[string]string{
+					"en": {"hello": "Hello", "bye": "Bye", "multi": "Two\nlines"},
+					"es": {"hello": "Hola", "multi": "Dos\nlíneas"},
+				},
+				Descriptions: map[string]string{"hello": "Greets the user"},
+			},
+		},
+		{
+			name: "single column",
+			args: args{
+				value: []byte("test\ntest"),
+				l:     &SourceFile{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty key",
+			args: args{
+				value: []byte("key,en\n,Hello"),
+				l:     &SourceFile{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate key",
+			args: args{
+				value: []byte("key,en\nhello,Hello\nhello,Hi"),
+				l:     &SourceFile{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate locale column",
+			args: args{
+				value: []byte("key,en,en\nhello,Hello,Hi"),
+				l:     &SourceFile{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := csvDecoder(',').Decode(tt.args.value, tt.args.l, Options{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(tt.args.l, tt.want) && !tt.wantErr {
+				t.Errorf("Decode() got = %v, want %v", tt.args.l, tt.want)
+			}
+		})
+	}
+}
+
+//go:embed mock/po
+var embedded embed.FS
+
+func TestLoad(t *testing.T) {
+	po, err := fs.Sub(embedded, "mock/po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	discard := log.New(io.Discard, "", 0)
+
+	tests := []struct {
+		name    string
+		fsys    fs.FS
+		o       Options
+		want    *Catalog
+		wantErr bool
+	}{
+		{
+			name: "map fs",
+			fsys: fstest.MapFS{
+				"messages/en.yaml":   {Data: []byte("hello: Hello {{.name}}\n")},
+				"messages/es.json":   {Data: []byte(`{"hello": "Hola {{.name}}"}`)},
+				"values/strings.xml": {Data: []byte(`<resources><string name="bye">Bye</string></resources>`)},
+				"notes.txt":          {Data: []byte("not a source file")},
+			},
+			o: Options{Logger: discard},
+			want: &Catalog{
+				Localizations: map[string]string{
+					"en.messages.hello": "Hello {{.name}}",
+					"es.messages.hello": "Hola {{.name}}",
+					"en.strings.bye":    "Bye",
+				},
+				Keys:         []string{"messages.hello", "strings.bye"},
+				Descriptions: map[string]string{},
+				Placeholders: map[string]map[string]string{},
+			},
+		},
+		{
+			name: "base locale",
+			fsys: fstest.MapFS{
+				"values/strings.xml": {Data: []byte(`<resources><string name="bye">Adiós</string></resources>`)},
+			},
+			o: Options{DefaultLocale: "es", Logger: discard},
+			want: &Catalog{
+				Localizations: map[string]string{"es.strings.bye": "Adiós"},
+				Keys:          []string{"strings.bye"},
+				Descriptions:  map[string]string{},
+				Placeholders:  map[string]map[string]string{},
+			},
+		},
+		{
+			name: "embed fs",
+			fsys: po,
+			o:    Options{Logger: discard},
+			want: &Catalog{
+				Localizations: map[string]string{
+					"es.catalog.hello":     "Hola",
+					"es.catalog.menu.open": "Abrir",
+					"es.catalog.apples.0":  "{{.count}} manzana",
+					"es.catalog.apples.1":  "{{.count}} manzanas",
+					"es.catalog.long":      "first line\nsecond \"line\"",
+				},
+				Keys:         []string{"catalog.apples.0", "catalog.apples.1", "catalog.hello", "catalog.long", "catalog.menu.open"},
+				Descriptions: map[string]string{},
+				Placeholders: map[string]map[string]string{},
+			},
+		},
+		{
+			name:    "invalid duplicates",
+			fsys:    fstest.MapFS{},
+			o:       Options{Duplicates: "random"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid csv delimiter",
+			fsys:    fstest.MapFS{},
+			o:       Options{CSVDelimiter: '"'},
+			wantErr: true,
+		},
+		{
+			name: "invalid file",
+			fsys: fstest.MapFS{
+				"en.json": {Data: []byte(`{"hello": `)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys, tt.o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "booleans kept as written",
+			value: "confirm:\n  no: No\n  yes: Yes\n  on: on\n  y: y\n",
+			want:  map[string]string{"confirm.no": "No", "confirm.yes": "Yes", "confirm.on": "on", "confirm.y": "y"},
+		},
+		{
+			name:  "numbers kept as written",
+			value: "version: 1.10\ncount: 0x1F\nlist:\n  - 1.50\n",
+			want:  map[string]string{"version": "1.10", "count": "0x1F", "list.0": "1.50"},
+		},
+		{
+			name:  "null",
+			value: "empty: ~\n",
+			want:  map[string]string{"empty": ""},
+		},
+		{
+			name:  "empty document",
+			value: "",
+			want:  map[string]string{},
+		},
+		{
+			name:    "not a mapping",
+			value:   "- one\n- two\n",
+			wantErr: true,
+		},
+		{
+			name:    "dotted and nested key",
+			value:   "a.b: flat\na:\n  b: nested\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseYAML([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseYAML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Localizations, tt.want) {
+				t.Errorf("parseYAML() got = %v, want %v", got.Localizations, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "numbers kept as written",
+			value: `{"count": 1000000, "version": 1.10, "ok": true}`,
+			want:  map[string]string{"count": "1000000", "version": "1.10", "ok": "true"},
+		},
+		{
+			name:    "dotted and nested key",
+			value:   `{"a.b": "flat", "a": {"b": "nested"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			value:   `{"hello": "hello"} {}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseJSON([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Localizations, tt.want) {
+				t.Errorf("parseJSON() got = %v, want %v", got.Localizations, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,536 @@
+package localize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	androidFileExt     = ".xml"
+	stringsFileExt     = ".strings"
+	stringsdictFileExt = ".stringsdict"
+)
+
+// androidDecoder decodes the Android string resources of values
+// directories, e.g. values-es/strings.xml.
+type androidDecoder struct{}
+
+// Decode implements Decoder.
+func (androidDecoder) Decode(value []byte, l *SourceFile, _ Options) error {
+	return parseAndroidStrings(value, l)
+}
+
+// Match implements Matcher. Other XML files, e.g. pom.xml, are not Android
+// resources, and the alternative resources of values directories with other
+// qualifiers, e.g. values-night, have no place in the localizations.
+func (androidDecoder) Match(name string) bool {
+	_, ok := androidResourceLocale(path.Base(path.Dir(name)))
+	return ok
+}
+
+// DirLocale implements DirLocaler, see androidResourceLocale.
+func (androidDecoder) DirLocale(dir string) (string, bool) {
+	return androidResourceLocale(dir)
+}
+
+// appleDecoder decodes the Apple .strings or .stringsdict files of .lproj
+// directories, e.g. es.lproj/Localizable.strings.
+type appleDecoder func(value []byte, l *SourceFile) error
+
+// Decode implements Decoder.
+func (fn appleDecoder) Decode(value []byte, l *SourceFile, _ Options) error {
+	return fn(value, l)
+}
+
+// DirLocale implements DirLocaler, see appleBundleLocale.
+func (appleDecoder) DirLocale(dir string) (string, bool) {
+	return appleBundleLocale(dir)
+}
+
+var androidRegion = regexp.MustCompile(`^r[A-Z]{2}$|^r[0-9]{3}$`)
+
+// androidResourceLocale returns the locale of an Android resource directory,
+// e.g. "es" for values-es, "es-MX" for values-es-rMX and "sr-Latn" for
+// values-b+sr+Latn. It is not ok for the directories with qualifiers other
+// than the locale, e.g. values-night or values-es-land.
+func androidResourceLocale(dir string) (string, bool) {
+	if dir == "values" {
+		return "", true
+	}
+	if !strings.HasPrefix(dir, "values-") {
+		return "", false
+	}
+
+	qualifiers := strings.Split(strings.TrimPrefix(dir, "values-"), "-")
+	if strings.HasPrefix(qualifiers[0], "b+") {
+		if len(qualifiers) > 1 {
+			return "", false
+		}
+		return strings.Join(strings.Split(qualifiers[0], "+")[1:], "-"), true
+	}
+	if !isLanguageCode(qualifiers[0]) {
+		return "", false
+	}
+
+	switch {
+	case len(qualifiers) == 1:
+		return qualifiers[0], true
+	case len(qualifiers) == 2 && androidRegion.MatchString(qualifiers[1]):
+		return qualifiers[0] + "-" + qualifiers[1][1:], true
+	default:
+		return "", false
+	}
+}
+
+// appleBundleLocale returns the locale of an Apple localization directory,
+// e.g. "es" for es.lproj.
+func appleBundleLocale(dir string) (string, bool) {
+	if !strings.HasSuffix(dir, ".lproj") {
+		return "", false
+	}
+	locale := strings.TrimSuffix(dir, ".lproj")
+	if locale == "Base" {
+		return "", true
+	}
+	return locale, true
+}
+
+// androidText is a <string> or an <item> of an Android resource file.
+type androidText struct {
+	Name     string
+	Quantity string
+	Text     string
+}
+
+func (t *androidText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			t.Name = attr.Value
+		case "quantity":
+			t.Quantity = attr.Value
+		}
+	}
+	text := xmlText{}
+	if err := text.UnmarshalXML(d, start); err != nil {
+		return err
+	}
+	t.Text = unescapeAndroid(text.Text)
+	return nil
+}
+
+type androidGroup struct {
+	Name  string        `xml:"name,attr"`
+	Items []androidText `xml:"item"`
+}
+
+type androidResources struct {
+	XMLName xml.Name
+	// Locale is the tools:locale of the base resources.
+	Locale  string         `xml:"locale,attr"`
+	Strings []androidText  `xml:"string"`
+	Plurals []androidGroup `xml:"plurals"`
+	Arrays  []androidGroup `xml:"string-array"`
+}
+
+// parseAndroidStrings decodes an Android string resource file. The items of
+// a <plurals> are keyed by their quantity, e.g. "apples.one", and those of a
+// <string-array> by their index, e.g. "planets.0".
+func parseAndroidStrings(value []byte, l *SourceFile) error {
+	resources := androidResources{}
+	if err := xml.Unmarshal(value, &resources); err != nil {
+		return err
+	}
+	if resources.XMLName.Local != "resources" {
+		return fmt.Errorf("root element is <%v>, not the <resources> of Android string resources", resources.XMLName.Local)
+	}
+
+	l.Locale = resources.Locale
+	l.Localizations = map[string]string{}
+	for _, s := range resources.Strings {
+		l.Localizations[s.Name] = s.Text
+	}
+	for _, plurals := range resources.Plurals {
+		for _, item := range plurals.Items {
+			l.Localizations[plurals.Name+"."+item.Quantity] = item.Text
+		}
+	}
+	for _, array := range resources.Arrays {
+		for i, item := range array.Items {
+			l.Localizations[array.Name+"."+strconv.Itoa(i)] = item.Text
+		}
+	}
+	return nil
+}
+
+// unescapeAndroid resolves the escapes of an Android string and collapses
+// its whitespace, except between double quotes.
+func unescapeAndroid(s string) string {
+	b := &strings.Builder{}
+	runes := []rune(strings.TrimSpace(s))
+	quoted := false
+	space := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			case 'u':
+				if i+4 < len(runes) {
+					if code, err := strconv.ParseUint(string(runes[i+1:i+5]), 16, 32); err == nil {
+						b.WriteRune(rune(code))
+						i += 4
+						break
+					}
+				}
+				b.WriteRune('u')
+			default:
+				b.WriteRune(runes[i])
+			}
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if !space {
+				b.WriteRune(' ')
+			}
+			space = true
+			continue
+		default:
+			b.WriteRune(r)
+		}
+		space = false
+	}
+	return b.String()
+}
+
+// parseAppleStrings decodes an Apple .strings file. The comment preceding an
+// entry becomes its description.
+func parseAppleStrings(value []byte, l *SourceFile) error {
+	text, err := decodeAppleText(value)
+	if err != nil {
+		return err
+	}
+
+	l.Localizations = map[string]string{}
+	l.Descriptions = map[string]string{}
+
+	s := &stringsScanner{src: []rune(text), line: 1}
+	for {
+		description, err := s.skipSpaceAndComments()
+		if err != nil {
+			return err
+		}
+		if s.eof() {
+			return nil
+		}
+
+		line := s.line
+		key, err := s.token()
+		if err != nil {
+			return err
+		}
+		if err := s.expect('='); err != nil {
+			return err
+		}
+		value, err := s.token()
+		if err != nil {
+			return err
+		}
+		if err := s.expect(';'); err != nil {
+			return err
+		}
+
+		if _, ok := l.Localizations[key]; ok {
+			return fmt.Errorf("line %d: duplicate key %q", line, key)
+		}
+		l.Localizations[key] = value
+		if description != "" && description != "No comment provided by engineer." {
+			l.Descriptions[key] = description
+		}
+	}
+}
+
+// decodeAppleText decodes .strings files, which are often UTF-16.
+func decodeAppleText(value []byte) (string, error) {
+	var order func([]byte) uint16
+	switch {
+	case bytes.HasPrefix(value, []byte{0xFF, 0xFE}):
+		order = func(b []byte) uint16 { return uint16(b[0]) | uint16(b[1])<<8 }
+	case bytes.HasPrefix(value, []byte{0xFE, 0xFF}):
+		order = func(b []byte) uint16 { return uint16(b[0])<<8 | uint16(b[1]) }
+	default:
+		value = bytes.TrimPrefix(value, []byte{0xEF, 0xBB, 0xBF})
+		if !utf8.Valid(value) {
+			return "", fmt.Errorf("not valid UTF-8 or UTF-16 with a byte order mark")
+		}
+		return string(value), nil
+	}
+
+	value = value[2:]
+	if len(value)%2 != 0 {
+		return "", fmt.Errorf("odd number of bytes in UTF-16 text")
+	}
+	units := make([]uint16, len(value)/2)
+	for i := range units {
+		units[i] = order(value[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+type stringsScanner struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (s *stringsScanner) eof() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *stringsScanner) next() rune {
+	r := s.src[s.pos]
+	s.pos++
+	if r == '\n' {
+		s.line++
+	}
+	return r
+}
+
+func (s *stringsScanner) peek(offset int) rune {
+	if s.pos+offset >= len(s.src) {
+		return 0
+	}
+	return s.src[s.pos+offset]
+}
+
+// skipSpaceAndComments returns the text of the last comment skipped.
+func (s *stringsScanner) skipSpaceAndComments() (string, error) {
+	comment := ""
+	for !s.eof() {
+		switch {
+		case unicode.IsSpace(s.peek(0)):
+			s.next()
+		case s.peek(0) == '/' && s.peek(1) == '*':
+			line := s.line
+			s.pos += 2
+			start := s.pos
+			for !(s.peek(0) == '*' && s.peek(1) == '/') {
+				if s.eof() {
+					return "", fmt.Errorf("line %d: unterminated comment", line)
+				}
+				s.next()
+			}
+			comment = strings.TrimSpace(string(s.src[start:s.pos]))
+			s.pos += 2
+		case s.peek(0) == '/' && s.peek(1) == '/':
+			start := s.pos + 2
+			for !s.eof() && s.peek(0) != '\n' {
+				s.next()
+			}
+			comment = strings.TrimSpace(string(s.src[start:s.pos]))
+		default:
+			return comment, nil
+		}
+	}
+	return comment, nil
+}
+
+// token reads a quoted string or an unquoted word.
+func (s *stringsScanner) token() (string, error) {
+	if _, err := s.skipSpaceAndComments(); err != nil {
+		return "", err
+	}
+	if s.eof() {
+		return "", fmt.Errorf("line %d: unexpected end of file", s.line)
+	}
+
+	if s.peek(0) != '"' {
+		start := s.pos
+		for !s.eof() && (unicode.IsLetter(s.peek(0)) || unicode.IsDigit(s.peek(0)) || strings.ContainsRune("_.-$:/", s.peek(0))) {
+			s.next()
+		}
+		if start == s.pos {
+			return "", fmt.Errorf("line %d: unexpected %q", s.line, s.peek(0))
+		}
+		return string(s.src[start:s.pos]), nil
+	}
+
+	line := s.line
+	s.next()
+	b := &strings.Builder{}
+	for {
+		if s.eof() {
+			return "", fmt.Errorf("line %d: unterminated string", line)
+		}
+		r := s.next()
+		switch r {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if s.eof() {
+				return "", fmt.Errorf("line %d: unterminated string", line)
+			}
+			switch e := s.next(); e {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			case 'r':
+				b.WriteRune('\r')
+			case 'U', 'u':
+				if s.pos+4 > len(s.src) {
+					return "", fmt.Errorf("line %d: invalid \\%c escape", s.line, e)
+				}
+				code, err := strconv.ParseUint(string(s.src[s.pos:s.pos+4]), 16, 16)
+				if err != nil {
+					return "", fmt.Errorf("line %d: invalid \\%c escape", s.line, e)
+				}
+				s.pos += 4
+				b.WriteRune(rune(code))
+			default:
+				b.WriteRune(e)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+func (s *stringsScanner) expect(r rune) error {
+	if _, err := s.skipSpaceAndComments(); err != nil {
+		return err
+	}
+	if s.eof() {
+		return fmt.Errorf("line %d: expected %q, found end of file", s.line, r)
+	}
+	if s.peek(0) != r {
+		return fmt.Errorf("line %d: expected %q, found %q", s.line, r, s.peek(0))
+	}
+	s.next()
+	return nil
+}
+
+// parseStringsDict decodes an Apple .stringsdict file. The format of an entry
+// is keyed by the entry itself, and the forms of its variables by the
+// variable and the plural category, e.g. "apples.count.one".
+func parseStringsDict(value []byte, l *SourceFile) error {
+	d := xml.NewDecoder(bytes.NewReader(value))
+	var root interface{}
+	for root == nil {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return fmt.Errorf("no property list found")
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local != "plist" {
+			if root, err = decodePlistValue(d, start); err != nil {
+				return err
+			}
+		}
+	}
+
+	entries, ok := root.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("the property list is not a dictionary")
+	}
+
+	l.Localizations = map[string]string{}
+	for key, entry := range entries {
+		rules, ok := entry.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("entry %q is not a dictionary", key)
+		}
+		for name, rule := range rules {
+			if name == "NSStringLocalizedFormatKey" {
+				l.Localizations[key] = fmt.Sprint(rule)
+				continue
+			}
+			forms, ok := rule.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for form, text := range forms {
+				if form == "NSStringFormatSpecTypeKey" || form == "NSStringFormatValueTypeKey" {
+					continue
+				}
+				l.Localizations[key+"."+name+"."+form] = fmt.Sprint(text)
+			}
+		}
+	}
+	return nil
+}
+
+// decodePlistValue decodes the XML property list element start.
+func decodePlistValue(d *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := map[string]interface{}{}
+		key := ""
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				if tok.Name.Local == "key" {
+					text := xmlText{}
+					if err := text.UnmarshalXML(d, tok); err != nil {
+						return nil, err
+					}
+					key = text.Text
+					continue
+				}
+				value, err := decodePlistValue(d, tok)
+				if err != nil {
+					return nil, err
+				}
+				dict[key] = value
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		var array []interface{}
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				value, err := decodePlistValue(d, tok)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			case xml.EndElement:
+				return array, nil
+			}
+		}
+	case "true", "false":
+		return start.Name.Local, d.Skip()
+	default:
+		text := xmlText{}
+		if err := text.UnmarshalXML(d, start); err != nil {
+			return nil, err
+		}
+		return text.Text, nil
+	}
+}
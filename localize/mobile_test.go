@@ -0,0 +1,237 @@
+package localize
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"unicode/utf16"
+)
+
+func Test_androidResourceLocale(t *testing.T) {
+	tests := []struct {
+		name   string
+		dir    string
+		want   string
+		wantOk bool
+	}{
+		{name: "base", dir: "values", want: "", wantOk: true},
+		{name: "language", dir: "values-es", want: "es", wantOk: true},
+		{name: "region", dir: "values-es-rMX", want: "es-MX", wantOk: true},
+		{name: "bcp 47", dir: "values-b+sr+Latn", want: "sr-Latn", wantOk: true},
+		{name: "other qualifier", dir: "values-night", want: "", wantOk: false},
+		{name: "locale and other qualifier", dir: "values-es-land", want: "", wantOk: false},
+		{name: "region and other qualifier", dir: "values-es-rMX-v21", want: "", wantOk: false},
+		{name: "network code", dir: "values-mcc310", want: "", wantOk: false},
+		{name: "not a language", dir: "values-ui", want: "", wantOk: false},
+		{name: "not values", dir: "layout", want: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := androidResourceLocale(tt.dir)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("androidResourceLocale() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_androidDecoder_Match(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want bool
+	}{
+		{name: "base", file: "res/values/strings.xml", want: true},
+		{name: "locale", file: "res/values-es-rMX/strings.xml", want: true},
+		{name: "other qualifier", file: "res/values-night/strings.xml", want: false},
+		{name: "smallest width", file: "res/values-sw600dp/strings.xml", want: false},
+		{name: "maven", file: "pom.xml", want: false},
+		{name: "layout", file: "res/layout/main.xml", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (androidDecoder{}).Match(tt.file); got != tt.want {
+				t.Errorf("androidDecoder.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_nonAndroidXML(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pom.xml":                      {Data: []byte("<project></project>")},
+		"res/values-night/strings.xml": {Data: []byte(`<resources><string name="hello">Hola</string></resources>`)},
+		"res/values-es/strings.xml":    {Data: []byte(`<resources><string name="hello">Hola</string></resources>`)},
+	}
+	logs := &bytes.Buffer{}
+	got, err := Load(fsys, Options{Logger: log.New(logs, "", 0)})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"es.res.strings.hello": "Hola"}
+	if !reflect.DeepEqual(got.Localizations, want) {
+		t.Errorf("Load() got = %v, want %v", got.Localizations, want)
+	}
+	for _, name := range []string{"pom.xml", "res/values-night/strings.xml"} {
+		if !strings.Contains(logs.String(), "skipping "+name) {
+			t.Errorf("Load() did not warn about %v, logs: %q", name, logs.String())
+		}
+	}
+}
+
+func Test_parseAndroidStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *SourceFile
+		wantErr bool
+	}{
+		{
+			name: "escapes and whitespace",
+			value: `<resources xmlns:tools="http://schemas.android.com/tools" tools:locale="de">
+				<string name="a">Don\'t   stop!</string>
+				<string name="b">"  keep   spaces  "</string>
+			</resources>`,
+			want: &SourceFile{
+				Locale: "de",
+				Localizations: map[string]string{
+					"a": "Don't stop!",
+					"b": "  keep   spaces  ",
+				},
+			},
+		},
+		{
+			name:    "not resources",
+			value:   `<manifest></manifest>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseAndroidStrings([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseAndroidStrings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAndroidStrings() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseAppleStrings(t *testing.T) {
+	utf16LE := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(`"hi" = "Hallo 🎉";`)) {
+		utf16LE = append(utf16LE, byte(u), byte(u>>8))
+	}
+
+	tests := []struct {
+		name    string
+		value   []byte
+		want    *SourceFile
+		wantErr bool
+	}{
+		{
+			name: "comments and escapes",
+			value: []byte("\xEF\xBB\xBF/* Title\n of the page */\n\"title\" = \"Caf\\U00e9\\n\";\n" +
+				"unquoted_key = \"value\"; // trailing\n"),
+			want: &SourceFile{
+				Localizations: map[string]string{
+					"title":        "Café\n",
+					"unquoted_key": "value",
+				},
+				Descriptions: map[string]string{"title": "Title\n of the page"},
+			},
+		},
+		{
+			name:  "utf-16",
+			value: utf16LE,
+			want: &SourceFile{
+				Localizations: map[string]string{"hi": "Hallo 🎉"},
+				Descriptions:  map[string]string{},
+			},
+		},
+		{
+			name:    "missing semicolon",
+			value:   []byte("\"a\" = \"b\"\n\"c\" = \"d\";"),
+			wantErr: true,
+		},
+		{
+			name:    "unterminated string",
+			value:   []byte(`"a" = "b;`),
+			wantErr: true,
+		},
+		{
+			name:    "duplicate key",
+			value:   []byte(`"a" = "b"; "a" = "c";`),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseAppleStrings(tt.value, got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseAppleStrings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAppleStrings() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseStringsDict(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "plural rule",
+			value: `<plist version="1.0"><dict><key>files</key><dict>
+				<key>NSStringLocalizedFormatKey</key><string>%#@n@ left</string>
+				<key>n</key><dict>
+					<key>NSStringFormatSpecTypeKey</key><string>NSStringPluralRuleType</string>
+					<key>zero</key><string>No files</string>
+					<key>other</key><string>%d files</string>
+				</dict>
+			</dict></dict></plist>`,
+			want: map[string]string{
+				"files":         "%#@n@ left",
+				"files.n.zero":  "No files",
+				"files.n.other": "%d files",
+			},
+		},
+		{
+			name:    "not a dictionary",
+			value:   `<plist version="1.0"><array><string>a</string></array></plist>`,
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			value:   `<plist version="1.0"></plist>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseStringsDict([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseStringsDict() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Localizations, tt.want) {
+				t.Errorf("parseStringsDict() got = %v, want %v", got.Localizations, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package localize
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Placeholders parses value the way the runtime does and returns the
+// sorted, distinct fields it references, e.g. "name" for {{.name}}.
+func Placeholders(value string) ([]string, error) {
+	tmpl, err := template.New("").Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]struct{}{}
+	if tmpl.Tree != nil {
+		collectFields(tmpl.Tree.Root, fields)
+	}
+
+	placeholders := make([]string, 0, len(fields))
+	for field := range fields {
+		placeholders = append(placeholders, field)
+	}
+	sort.Strings(placeholders)
+	return placeholders, nil
+}
+
+func collectFields(node parse.Node, fields map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, fields)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, fields)
+	case *parse.IfNode:
+		collectBranchFields(&n.BranchNode, fields)
+	case *parse.RangeNode:
+		collectBranchFields(&n.BranchNode, fields)
+	case *parse.WithNode:
+		collectBranchFields(&n.BranchNode, fields)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, fields)
+		}
+	case *parse.ChainNode:
+		collectFields(n.Node, fields)
+	case *parse.FieldNode:
+		fields[strings.Join(n.Ident, ".")] = struct{}{}
+	}
+}
+
+func collectBranchFields(n *parse.BranchNode, fields map[string]struct{}) {
+	collectFields(n.Pipe, fields)
+	collectFields(n.List, fields)
+	collectFields(n.ElseList, fields)
+}
@@ -0,0 +1,48 @@
+package localize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Placeholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "none",
+			value: "hello",
+			want:  []string{},
+		},
+		{
+			name:  "fields",
+			value: "Hello {{.lastname}}, {{.firstname}} {{.lastname}}",
+			want:  []string{"firstname", "lastname"},
+		},
+		{
+			name:  "nested",
+			value: "{{if .count}}{{.count}} items{{else}}{{.empty}}{{end}} for {{.user.name | printf \"%s\"}}",
+			want:  []string{"count", "empty", "user.name"},
+		},
+		{
+			name:    "syntax error",
+			value:   "Hello {{.name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Placeholders(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Placeholders() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Placeholders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
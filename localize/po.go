@@ -0,0 +1,199 @@
+package localize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	poFileExt  = ".po"
+	potFileExt = ".pot"
+)
+
+// poEntry is a message of a gettext PO file.
+type poEntry struct {
+	line       int
+	fuzzy      bool
+	hasContext bool
+	context    string
+	hasID      bool
+	id         string
+	plural     bool
+	idPlural   string
+	strs       map[int]string
+}
+
+// parsePO decodes a gettext PO file. The key of a message is its msgid,
+// prefixed by its msgctxt and a dot when it has one, and the forms of a
+// plural message are keyed by their index, e.g. "apples.0" and "apples.1".
+// Untranslated and fuzzy messages are skipped. The Language header, when
+// set, gives the locale of the file.
+func parsePO(value []byte, l *SourceFile) error {
+	return decodePO(value, l, false)
+}
+
+// parsePOT decodes a gettext POT template, whose messages have no
+// translations, using the msgid and msgid_plural as the values.
+func parsePOT(value []byte, l *SourceFile) error {
+	return decodePO(value, l, true)
+}
+
+func decodePO(value []byte, l *SourceFile, template bool) error {
+	entries, err := readPOEntries(string(value))
+	if err != nil {
+		return err
+	}
+
+	l.Localizations = map[string]string{}
+	for _, e := range entries {
+		if e.id == "" && !e.hasContext {
+			l.Locale = poHeaderField(e.strs[0], "Language")
+			continue
+		}
+
+		key := e.id
+		if e.hasContext {
+			key = e.context + "." + e.id
+		}
+
+		if e.fuzzy {
+			l.Warnings = append(l.Warnings, fmt.Sprintf("line %d: skipping fuzzy message %q", e.line, key))
+			continue
+		}
+
+		strs := e.strs
+		if template {
+			strs = map[int]string{0: e.id}
+			if e.plural {
+				strs[1] = e.idPlural
+			}
+		}
+
+		if !e.plural {
+			if strs[0] != "" {
+				l.Localizations[key] = strs[0]
+			}
+			continue
+		}
+		for n, str := range strs {
+			if str != "" {
+				l.Localizations[key+"."+strconv.Itoa(n)] = str
+			}
+		}
+	}
+	return nil
+}
+
+func readPOEntries(value string) ([]*poEntry, error) {
+	var entries []*poEntry
+	cur := &poEntry{strs: map[int]string{}}
+	// next starts a new entry once the current one has its translation.
+	next := func() {
+		if len(cur.strs) > 0 {
+			entries = append(entries, cur)
+			cur = &poEntry{strs: map[int]string{}}
+		}
+	}
+
+	// appendTo adds continuation lines to the string of the last keyword.
+	var appendTo func(string)
+	appendToString := func(s *string) func(string) {
+		return func(str string) { *s += str }
+	}
+	lines := strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		n := i + 1
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "", strings.HasPrefix(line, "#~"):
+			appendTo = nil
+			continue
+		case strings.HasPrefix(line, "#,"):
+			next()
+			for _, flag := range strings.Split(line[2:], ",") {
+				if strings.TrimSpace(flag) == "fuzzy" {
+					cur.fuzzy = true
+				}
+			}
+			appendTo = nil
+			continue
+		case strings.HasPrefix(line, "#"):
+			appendTo = nil
+			continue
+		case strings.HasPrefix(line, `"`):
+			if appendTo == nil {
+				return nil, fmt.Errorf("line %d: string without a keyword", n)
+			}
+			str, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+			appendTo(str)
+			continue
+		}
+
+		keyword, rest := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			keyword, rest = line[:i], strings.TrimSpace(line[i:])
+		}
+		str, err := strconv.Unquote(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v: %v", n, keyword, err)
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			next()
+			cur.hasContext, cur.context = true, str
+			appendTo = appendToString(&cur.context)
+		case keyword == "msgid":
+			next()
+			cur.line = n
+			cur.hasID, cur.id = true, str
+			appendTo = appendToString(&cur.id)
+		case keyword == "msgid_plural":
+			if !cur.hasID {
+				return nil, fmt.Errorf("line %d: msgid_plural without msgid", n)
+			}
+			cur.plural, cur.idPlural = true, str
+			appendTo = appendToString(&cur.idPlural)
+		case strings.HasPrefix(keyword, "msgstr"):
+			if !cur.hasID {
+				return nil, fmt.Errorf("line %d: msgstr without msgid", n)
+			}
+			index := 0
+			if keyword != "msgstr" {
+				index, err = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+				if err != nil || !strings.HasSuffix(keyword, "]") {
+					return nil, fmt.Errorf("line %d: invalid keyword %v", n, keyword)
+				}
+			}
+			cur.strs[index] = str
+			e := cur
+			appendTo = func(str string) { e.strs[index] += str }
+		default:
+			return nil, fmt.Errorf("line %d: unknown keyword %v", n, keyword)
+		}
+	}
+
+	if cur.hasID && len(cur.strs) == 0 {
+		return nil, fmt.Errorf("line %d: msgid without msgstr", cur.line)
+	}
+	next()
+
+	return entries, nil
+}
+
+// poHeaderField returns the value of field in the header of a PO file,
+// e.g. "es" for "Language: es".
+func poHeaderField(header, field string) string {
+	for _, line := range strings.Split(header, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == field {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package localize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parsePO(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *SourceFile
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			value: `msgid ""
+msgstr ""
+"Language: es\n"
+
+msgid "hello"
+msgstr "Hola"
+
+msgid "long"
+msgstr ""
+"first line\n"
+"second \"line\""
+
+msgid "untranslated"
+msgstr ""
+`,
+			want: &SourceFile{
+				Locale: "es",
+				Localizations: map[string]string{
+					"hello": "Hola",
+					"long":  "first line\nsecond \"line\"",
+				},
+			},
+		},
+		{
+			name: "context and plural",
+			value: `msgctxt "menu"
+msgid "open"
+msgstr "Abrir"
+
+msgid "apple"
+msgid_plural "apples"
+msgstr[0] "{{.count}} manzana"
+msgstr[1] "{{.count}} manzanas"
+`,
+			want: &SourceFile{
+				Localizations: map[string]string{
+					"menu.open": "Abrir",
+					"apple.0":   "{{.count}} manzana",
+					"apple.1":   "{{.count}} manzanas",
+				},
+			},
+		},
+		{
+			name: "fuzzy and obsolete",
+			value: `#: main.go:10
+#, fuzzy, c-format
+msgid "bye"
+msgstr "Adiós"
+
+# comment
+msgid "hello"
+msgstr "Hola"
+
+#~ msgid "obsolete"
+#~ msgstr "obsoleto"
+`,
+			want: &SourceFile{
+				Localizations: map[string]string{"hello": "Hola"},
+				Warnings:      []string{`line 3: skipping fuzzy message "bye"`},
+			},
+		},
+		{
+			name:    "msgid without msgstr",
+			value:   "msgid \"hello\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown keyword",
+			value:   "msgid \"hello\"\nmsgtxt \"Hola\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid string",
+			value:   "msgid \"hello\nmsgstr \"Hola\"\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parsePO([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parsePO() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePO() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parsePOT(t *testing.T) {
+	value := `msgid ""
+msgstr ""
+"Language: \n"
+
+msgid "hello"
+msgstr ""
+
+msgid "apple"
+msgid_plural "apples"
+msgstr[0] ""
+msgstr[1] ""
+`
+	want := &SourceFile{
+		Localizations: map[string]string{
+			"hello":   "hello",
+			"apple.0": "apple",
+			"apple.1": "apples",
+		},
+	}
+
+	got := &SourceFile{}
+	if err := parsePOT([]byte(value), got); err != nil {
+		t.Fatalf("parsePOT() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePOT() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package localize
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const propertiesFileExt = ".properties"
+
+// propertiesDecoder decodes Java .properties files, named like resource
+// bundles, e.g. messages_es.properties.
+type propertiesDecoder struct{}
+
+// Decode implements Decoder.
+func (propertiesDecoder) Decode(value []byte, l *SourceFile, _ Options) error {
+	return parseProperties(value, l)
+}
+
+// NameLocale implements NameLocaler, see javaBundleLocale.
+func (propertiesDecoder) NameLocale(name string) (string, string, bool) {
+	return javaBundleLocale(name)
+}
+
+var javaBundleSuffix = regexp.MustCompile(`^(.+?)_([a-z]{2,3})(?:_([A-Z]{2}|[0-9]{3}))?$`)
+
+// javaBundleLocale returns the locale of a Java resource bundle file name,
+// e.g. "es" for messages_es and "es-MX" for messages_es_MX. A file name
+// without a locale, e.g. messages or app_ui, is the base bundle, unless the
+// name is a language code itself.
+func javaBundleLocale(name string) (string, string, bool) {
+	match := javaBundleSuffix.FindStringSubmatch(name)
+	if match == nil || !isLanguageCode(match[2]) {
+		if isLanguageCode(name) {
+			return "", "", false
+		}
+		return "", name, true
+	}
+	locale := match[2]
+	if match[3] != "" {
+		locale += "-" + match[3]
+	}
+	return locale, match[1], true
+}
+
+// parseProperties decodes a Java .properties file. Lines ending with an odd
+// number of backslashes continue on the next line, and the escapes of keys
+// and values, including \uXXXX, are resolved. A key defined several times
+// takes its last value.
+func parseProperties(value []byte, l *SourceFile) error {
+	l.Localizations = map[string]string{}
+
+	lines := strings.Split(strings.ReplaceAll(string(value), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		for continues(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continues(line) {
+			// The last line of the file has nothing to continue on.
+			line = line[:len(line)-1]
+		}
+
+		key, value := splitProperty(line)
+		key, err := unescapeProperty(key)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+		value, err = unescapeProperty(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+		l.Localizations[key] = value
+	}
+	return nil
+}
+
+// continues reports whether line ends with an unescaped backslash.
+func continues(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// splitProperty splits line at the first unescaped '=', ':' or whitespace.
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = strings.TrimLeft(rest[1:], " \t\f")
+			}
+			return line[:i], rest
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:])
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:i+5])
+			}
+			i += 4
+			r := rune(code)
+			// Characters outside the BMP are written as surrogate pairs.
+			if utf16.IsSurrogate(r) && i+7 <= len(s) && strings.HasPrefix(s[i+1:], `\u`) {
+				if low, err := strconv.ParseUint(s[i+3:i+7], 16, 16); err == nil {
+					r = utf16.DecodeRune(r, rune(low))
+					i += 6
+				}
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
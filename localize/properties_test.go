@@ -0,0 +1,89 @@
+package localize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_javaBundleLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		wantLocale string
+		wantBase   string
+		wantOk     bool
+	}{
+		{name: "language", file: "messages_es", wantLocale: "es", wantBase: "messages", wantOk: true},
+		{name: "region", file: "messages_es_MX", wantLocale: "es-MX", wantBase: "messages", wantOk: true},
+		{name: "underscored base", file: "error_messages_fr", wantLocale: "fr", wantBase: "error_messages", wantOk: true},
+		{name: "base bundle", file: "messages", wantLocale: "", wantBase: "messages", wantOk: true},
+		{name: "three letter base bundle", file: "app", wantLocale: "", wantBase: "app", wantOk: true},
+		{name: "not a language", file: "app_ui", wantLocale: "", wantBase: "app_ui", wantOk: true},
+		{name: "not a three letter language", file: "error_msg", wantLocale: "", wantBase: "error_msg", wantOk: true},
+		{name: "three letter language", file: "messages_fil", wantLocale: "fil", wantBase: "messages", wantOk: true},
+		{name: "not a language before a language", file: "app_ui_es", wantLocale: "es", wantBase: "app_ui", wantOk: true},
+		{name: "locale only", file: "es", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, base, ok := javaBundleLocale(tt.file)
+			if locale != tt.wantLocale || base != tt.wantBase || ok != tt.wantOk {
+				t.Errorf("javaBundleLocale() got = %v, %v, %v, want %v, %v, %v",
+					locale, base, ok, tt.wantLocale, tt.wantBase, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_parseProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "separators",
+			value: "a=1\nb : 2\nc 3\nd\n" +
+				"  ! comment\n# comment\n",
+			want: map[string]string{"a": "1", "b": "2", "c": "3", "d": ""},
+		},
+		{
+			name:  "escapes",
+			value: "key\\ with\\=sep = tab\\there\\nnew line \\\\ \\ud83c\\udf89\n",
+			want:  map[string]string{"key with=sep": "tab\there\nnew line \\ 🎉"},
+		},
+		{
+			name:  "continuations",
+			value: "long = one, \\\n       two, \\\n       three\nescaped = ends with \\\\\nnext = line\n",
+			want: map[string]string{
+				"long":    "one, two, three",
+				"escaped": "ends with \\",
+				"next":    "line",
+			},
+		},
+		{
+			name:  "last value wins",
+			value: "a = 1\r\na = 2\r\n",
+			want:  map[string]string{"a": "2"},
+		},
+		{
+			name:    "invalid unicode escape",
+			value:   "a = \\u00zz\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &SourceFile{}
+			err := parseProperties([]byte(tt.value), got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseProperties() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Localizations, tt.want) {
+				t.Errorf("parseProperties() got = %v, want %v", got.Localizations, tt.want)
+			}
+		})
+	}
+}
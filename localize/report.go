@@ -0,0 +1,176 @@
+package localize
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// ReportFormatText writes the report as indented text.
+	ReportFormatText = "text"
+	// ReportFormatJSON writes the report as JSON.
+	ReportFormatJSON = "json"
+)
+
+// Report describes the problems found in the localizations of every locale,
+// compared to a reference locale.
+type Report struct {
+	Reference string `json:"reference"`
+	// Missing holds, per locale, the keys of the reference locale it has no
+	// localization for.
+	Missing map[string][]string `json:"missing,omitempty"`
+	// Placeholders holds, per locale, the keys whose placeholders differ from
+	// those of the reference locale.
+	Placeholders map[string][]PlaceholderMismatch `json:"placeholders,omitempty"`
+}
+
+// PlaceholderMismatch is a key whose placeholders differ from those of the
+// reference locale.
+type PlaceholderMismatch struct {
+	Key       string   `json:"key"`
+	Reference []string `json:"reference"`
+	Found     []string `json:"found"`
+}
+
+// newReport compares the localizations of every locale with those of the
+// reference locale.
+func newReport(reference string, localizations map[string]string) Report {
+	r := Report{
+		Reference:    reference,
+		Missing:      map[string][]string{},
+		Placeholders: map[string][]PlaceholderMismatch{},
+	}
+
+	byLocale := map[string]map[string]string{}
+	for fullKey, value := range localizations {
+		locale, key := splitLocalizationKey(fullKey)
+		if byLocale[locale] == nil {
+			byLocale[locale] = map[string]string{}
+		}
+		byLocale[locale][key] = value
+	}
+
+	for locale, values := range byLocale {
+		if locale == reference {
+			continue
+		}
+		for key, referenceValue := range byLocale[reference] {
+			value, ok := values[key]
+			if !ok {
+				r.Missing[locale] = append(r.Missing[locale], key)
+				continue
+			}
+			// Syntax errors are caught while reading the source files.
+			want, _ := Placeholders(referenceValue)
+			got, _ := Placeholders(value)
+			if !reflect.DeepEqual(want, got) {
+				r.Placeholders[locale] = append(r.Placeholders[locale], PlaceholderMismatch{
+					Key:       key,
+					Reference: want,
+					Found:     got,
+				})
+			}
+		}
+		sort.Strings(r.Missing[locale])
+		sort.Slice(r.Placeholders[locale], func(i, j int) bool {
+			return r.Placeholders[locale][i].Key < r.Placeholders[locale][j].Key
+		})
+	}
+
+	return r
+}
+
+// Count returns the number of problems in the report.
+func (r Report) Count() int {
+	n := 0
+	for _, keys := range r.Missing {
+		n += len(keys)
+	}
+	for _, mismatches := range r.Placeholders {
+		n += len(mismatches)
+	}
+	return n
+}
+
+// Write writes the report to w in format, ReportFormatText or
+// ReportFormatJSON.
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case ReportFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case ReportFormatText:
+		for _, locale := range sortedKeys(r.Missing) {
+			keys := r.Missing[locale]
+			if len(keys) == 0 {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%v: %d missing compared to %v\n", locale, len(keys), r.Reference); err != nil {
+				return err
+			}
+			for _, key := range keys {
+				if _, err := fmt.Fprintf(w, "\t%v\n", key); err != nil {
+					return err
+				}
+			}
+		}
+		for _, locale := range sortedMismatchKeys(r.Placeholders) {
+			mismatches := r.Placeholders[locale]
+			if _, err := fmt.Fprintf(w, "%v: %d with different placeholders than %v\n", locale, len(mismatches), r.Reference); err != nil {
+				return err
+			}
+			for _, m := range mismatches {
+				if _, err := fmt.Fprintf(w, "\t%v: %v has %v, %v has %v\n", m.Key,
+					r.Reference, formatPlaceholders(m.Reference), locale, formatPlaceholders(m.Found)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		return errReportFormatInvalid
+	}
+}
+
+// splitLocalizationKey splits a "<locale>.<key>" localization key.
+func splitLocalizationKey(fullKey string) (string, string) {
+	parts := strings.SplitN(fullKey, ".", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+func formatPlaceholders(placeholders []string) string {
+	if len(placeholders) == 0 {
+		return "no placeholders"
+	}
+	fields := make([]string, len(placeholders))
+	for i, placeholder := range placeholders {
+		fields[i] = "{{." + placeholder + "}}"
+	}
+	return strings.Join(fields, " ")
+}
+
+func sortedMismatchKeys(m map[string][]PlaceholderMismatch) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,111 @@
+package localize
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var reportLocalizations = map[string]string{
+	"en.messages.hello": "hello",
+	"en.messages.name":  "my name is {{.name}}",
+	"es.messages.name":  "me llamo {{.nombre}}",
+	"fr.messages.name":  "je m'appelle {{.name}}",
+	"en.messages.bye":   "bye",
+	"es.messages.hello": "hola",
+	"es.messages.extra": "extra",
+	"fr.messages.hello": "bonjour",
+	"fr.messages.bye":   "au revoir",
+}
+
+func Test_newReport(t *testing.T) {
+	type args struct {
+		reference     string
+		localizations map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want Report
+	}{
+		{
+			name: "valid",
+			args: args{"en", reportLocalizations},
+			want: Report{
+				Reference: "en",
+				Missing:   map[string][]string{"es": {"messages.bye"}},
+				Placeholders: map[string][]PlaceholderMismatch{
+					"es": {{Key: "messages.name", Reference: []string{"name"}, Found: []string{"nombre"}}},
+				},
+			},
+		},
+		{
+			name: "other reference",
+			args: args{"es", reportLocalizations},
+			want: Report{
+				Reference: "es",
+				Missing: map[string][]string{
+					"en": {"messages.extra"},
+					"fr": {"messages.extra"},
+				},
+				Placeholders: map[string][]PlaceholderMismatch{
+					"en": {{Key: "messages.name", Reference: []string{"nombre"}, Found: []string{"name"}}},
+					"fr": {{Key: "messages.name", Reference: []string{"nombre"}, Found: []string{"name"}}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newReport(tt.args.reference, tt.args.localizations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newReport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	r := Report{
+		Reference: "en",
+		Missing:   map[string][]string{"es": {"messages.bye", "messages.hello"}},
+		Placeholders: map[string][]PlaceholderMismatch{
+			"fr": {{Key: "messages.name", Reference: []string{"name"}, Found: []string{}}},
+		},
+	}
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "text",
+			format: ReportFormatText,
+			want: "es: 2 missing compared to en\n\tmessages.bye\n\tmessages.hello\n" +
+				"fr: 1 with different placeholders than en\n\tmessages.name: en has {{.name}}, fr has no placeholders\n",
+		},
+		{
+			name:   "json",
+			format: ReportFormatJSON,
+			want: "{\n  \"reference\": \"en\",\n  \"missing\": {\n    \"es\": [\n      \"messages.bye\",\n      \"messages.hello\"\n    ]\n  },\n" +
+				"  \"placeholders\": {\n    \"fr\": [\n      {\n        \"key\": \"messages.name\",\n        \"reference\": [\n          \"name\"\n        ],\n        \"found\": []\n      }\n    ]\n  }\n}\n",
+		},
+		{
+			name:    "invalid format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := r.Write(w, tt.format); (err != nil) != tt.wantErr {
+				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := w.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("Write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
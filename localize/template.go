@@ -0,0 +1,121 @@
+package localize
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// tmplValues are the values packageTemplate is executed with.
+type tmplValues struct {
+	// Timestamp is the generation time, zero unless requested.
+	Timestamp time.Time
+	Hash      string
+	Keys      map[string]string
+	// Descriptions documents the keys, not the constant names, of Keys.
+	Descriptions map[string]string
+	// Placeholders documents the placeholder types of the keys of Keys.
+	Placeholders  map[string]map[string]string
+	Localizations map[string]string
+	Package       string
+	// RuntimeImport is the import path of the runtime package, or empty when
+	// the runtime is emitted into the generated package.
+	RuntimeImport string
+	// Runtime qualifies the runtime identifiers, e.g. "i18n.".
+	Runtime        string
+	DefaultLocale  string
+	FallbackLocale string
+}
+
+// packageTemplate renders the generated package. Keys and localizations come
+// straight from the source files, so they must only ever be written through
+// quote, which produces a valid Go string literal for any input.
+var packageTemplate = template.Must(template.New("").Funcs(template.FuncMap{
+	"quote":        strconv.Quote,
+	"comment":      comment,
+	"placeholders": placeholdersComment,
+}).Parse(`// Code generated by go-localize; DO NOT EDIT.
+// Localizations hash: sha256:{{ .Hash }}
+{{- if not .Timestamp.IsZero }}
+// This file was generated by robots at
+// {{ .Timestamp }}
+{{- end }}
+
+package {{ .Package }}
+{{- if .RuntimeImport }}
+
+import (
+	"{{ .RuntimeImport }}"
+)
+{{- end }}
+
+var l = {{ .Runtime }}New({{ quote .DefaultLocale }}, {{ quote .FallbackLocale }}, localizations)
+
+// Get returns the localization of key in the default locale.
+func Get(key {{ .Runtime }}Key, replacements ...*{{ .Runtime }}Replacements) string {
+	return l.Get(key, replacements...)
+}
+
+// GetWithLocale returns the localization of key in locale.
+func GetWithLocale(locale string, key {{ .Runtime }}Key, replacements ...*{{ .Runtime }}Replacements) string {
+	return l.GetWithLocale(locale, key, replacements...)
+}
+
+const (
+{{- range $key, $element := .Keys }}
+{{- with index $.Descriptions $element }}
+	{{ comment . }}
+{{- end }}
+{{- with index $.Placeholders $element }}
+	{{ comment (placeholders .) }}
+{{- end }}
+	{{ $key }} {{ $.Runtime }}Key = {{ quote $element }}
+{{- end }}
+)
+
+var localizations = map[string]string{
+{{- range $key, $element := .Localizations }}
+	{{ quote $key }}: {{ quote $element }},
+{{- end }}
+}
+`,
+))
+
+// comment turns text into a line comment, however many lines it has.
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(text, "\r", "")), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+strings.TrimSpace(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// arbGoTypes are the Go types of the ARB placeholder types.
+var arbGoTypes = map[string]string{
+	"String":   "string",
+	"int":      "int",
+	"double":   "float64",
+	"num":      "float64",
+	"DateTime": "time.Time",
+}
+
+// placeholdersComment documents the placeholders of a key and their types,
+// e.g. "Placeholders: {{.count}} int, {{.name}} string".
+func placeholdersComment(placeholders map[string]string) string {
+	names := make([]string, 0, len(placeholders))
+	for name := range placeholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		typ := "interface{}"
+		if goType, ok := arbGoTypes[placeholders[name]]; ok {
+			typ = goType
+		}
+		names[i] = fmt.Sprintf("{{.%v}} %v", name, typ)
+	}
+	return "Placeholders: " + strings.Join(names, ", ")
+}
@@ -0,0 +1,11 @@
+package localize
+
+import "testing"
+
+func Test_placeholdersComment(t *testing.T) {
+	got := placeholdersComment(map[string]string{"name": "String", "when": "DateTime", "extra": "Object"})
+	want := "Placeholders: {{.extra}} interface{}, {{.name}} string, {{.when}} time.Time"
+	if got != want {
+		t.Errorf("placeholdersComment() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package localize
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// GeneratorTS is the name of the generator of the TypeScript definitions.
+const GeneratorTS = "ts"
+
+// tsFileName is the name of the TypeScript definitions, without extension.
+const tsFileName = "keys"
+
+// arbTSTypes are the TypeScript types of the ARB placeholder types.
+var arbTSTypes = map[string]string{
+	"String":   "string",
+	"int":      "number",
+	"double":   "number",
+	"num":      "number",
+	"DateTime": "Date",
+}
+
+// tsTemplate renders the TypeScript definitions. Like in packageTemplate,
+// keys and descriptions come straight from the source files and must only be
+// written through jsString and jsdoc.
+var tsTemplate = template.Must(template.New("").Funcs(template.FuncMap{
+	"quote": jsString,
+	"jsdoc": jsdoc,
+}).Parse(`// Code generated by go-localize; DO NOT EDIT.
+// Localizations hash: sha256:{{ .Hash }}
+
+/** A localization key, without its locale. */
+export type Key =
+{{- range .Keys }}
+  | {{ quote .Key }}
+{{- else }} never
+{{- end }};
+
+/** The placeholder parameters of every key, by name. */
+export interface Params {
+{{- range .Keys }}
+{{- with .Description }}
+  {{ jsdoc . }}
+{{- end }}
+  {{ quote .Key }}: {{ if .Params }}{ {{ range $i, $p := .Params }}{{ if $i }}; {{ end }}{{ quote $p.Name }}: {{ $p.Type }}{{ end }} }{{ else }}Record<string, never>{{ end }};
+{{- end }}
+}
+`))
+
+type tsKey struct {
+	Key         string
+	Description string
+	Params      []tsParam
+}
+
+type tsParam struct {
+	Name string
+	Type string
+}
+
+// renderTypeScript renders, in dir, the TypeScript definitions of the keys
+// of cat: a Key union type of every key, and a Params interface mapping
+// every key to its placeholder parameters. Parameters are those of the
+// {{.name}} fields of the key in every locale, typed by the ARB placeholder
+// types when declared. The file is "keys.d.ts" when o.TSDeclaration is set,
+// and "keys.ts" otherwise.
+func renderTypeScript(dir string, cat *Catalog, o Options) ([]File, error) {
+	fields := map[string]map[string]struct{}{}
+	for fullKey, value := range cat.Localizations {
+		_, key := splitLocalizationKey(fullKey)
+		// Syntax errors are caught while reading the source files.
+		placeholders, _ := Placeholders(value)
+		if fields[key] == nil {
+			fields[key] = map[string]struct{}{}
+		}
+		for _, placeholder := range placeholders {
+			fields[key][placeholder] = struct{}{}
+		}
+	}
+
+	keys := make([]tsKey, 0, len(cat.Keys))
+	for _, key := range cat.Keys {
+		params := map[string]string{}
+		for field := range fields[key] {
+			// {{.user.name}} reads the name of the user parameter.
+			name := strings.SplitN(field, ".", 2)[0]
+			typ := "string | number"
+			if name != field {
+				typ = "Record<string, unknown>"
+			} else if arbType, ok := cat.Placeholders[key][name]; ok {
+				typ = "unknown"
+				if tsType, ok := arbTSTypes[arbType]; ok {
+					typ = tsType
+				}
+			}
+			params[name] = typ
+		}
+
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		k := tsKey{Key: key, Description: cat.Descriptions[key]}
+		for _, name := range names {
+			k.Params = append(k.Params, tsParam{Name: name, Type: params[name]})
+		}
+		keys = append(keys, k)
+	}
+
+	b := &bytes.Buffer{}
+	err := tsTemplate.Execute(b, struct {
+		Hash string
+		Keys []tsKey
+	}{Hash: hashLocalizations(cat.Localizations), Keys: keys})
+	if err != nil {
+		return nil, err
+	}
+
+	name := tsFileName + ".ts"
+	if o.TSDeclaration {
+		name = tsFileName + ".d.ts"
+	}
+	return []File{{Path: filepath.Join(dir, name), Content: b.Bytes()}}, nil
+}
+
+// jsString returns s as a JavaScript string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// jsdoc turns text into a JSDoc comment, however many lines it has.
+func jsdoc(text string) string {
+	text = strings.ReplaceAll(text, "*/", "*\\/")
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(text, "\r", "")), "\n")
+	if len(lines) == 1 {
+		return "/** " + strings.TrimSpace(lines[0]) + " */"
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("   * "+strings.TrimSpace(line), " ")
+	}
+	return "/**\n" + strings.Join(lines, "\n") + "\n   */"
+}
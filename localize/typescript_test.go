@@ -0,0 +1,90 @@
+package localize
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_renderTypeScript(t *testing.T) {
+	cat := &Catalog{
+		Localizations: map[string]string{
+			"en.checkout.pay":  "Pay {{.amount}}",
+			"es.checkout.pay":  "Pagar {{.amount}} {{.currency}}",
+			"en.hello":         "Hello {{.user.name}}",
+			"en.title":         "Checkout",
+			`en.quoted "key"`:  "value",
+			"en.checkout.when": "On {{.date}}",
+		},
+		Keys:         []string{"checkout.pay", "checkout.when", "hello", `quoted "key"`, "title"},
+		Descriptions: map[string]string{"title": "The title.\nShown */ on top."},
+		Placeholders: map[string]map[string]string{"checkout.pay": {"amount": "double"}, "checkout.when": {"date": "DateTime"}},
+	}
+	hash := hashLocalizations(cat.Localizations)
+	want := `// Code generated by go-localize; DO NOT EDIT.
+// Localizations hash: sha256:` + hash + `
+
+/** A localization key, without its locale. */
+export type Key =
+  | "checkout.pay"
+  | "checkout.when"
+  | "hello"
+  | "quoted \"key\""
+  | "title";
+
+/** The placeholder parameters of every key, by name. */
+export interface Params {
+  "checkout.pay": { "amount": number; "currency": string | number };
+  "checkout.when": { "date": Date };
+  "hello": { "user": Record<string, unknown> };
+  "quoted \"key\"": Record<string, never>;
+  /**
+   * The title.
+   * Shown *\/ on top.
+   */
+  "title": Record<string, never>;
+}
+`
+	tests := []struct {
+		name     string
+		cat      *Catalog
+		o        Options
+		wantPath string
+		want     string
+	}{
+		{
+			name:     "ts",
+			cat:      cat,
+			wantPath: filepath.Join("web", "keys.ts"),
+			want:     want,
+		},
+		{
+			name:     "declaration",
+			cat:      cat,
+			o:        Options{TSDeclaration: true},
+			wantPath: filepath.Join("web", "keys.d.ts"),
+			want:     want,
+		},
+		{
+			name:     "no keys",
+			cat:      &Catalog{},
+			wantPath: filepath.Join("web", "keys.ts"),
+			want: strings.Replace(want[:strings.Index(want, "export type")], hash, hashLocalizations(nil), 1) +
+				"export type Key = never;\n\n/** The placeholder parameters of every key, by name. */\nexport interface Params {\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := renderTypeScript("web", tt.cat, tt.o)
+			if err != nil {
+				t.Fatalf("renderTypeScript() error = %v", err)
+			}
+			if len(files) != 1 || files[0].Path != tt.wantPath {
+				t.Fatalf("renderTypeScript() files = %v, want %v", files, tt.wantPath)
+			}
+			if got := string(files[0].Content); got != tt.want {
+				t.Errorf("renderTypeScript() got:\n%v\nwant:\n%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package localize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	xlfFileExt   = ".xlf"
+	xliffFileExt = ".xliff"
+)
+
+// xmlText is the text of an element, including the text of inline elements
+// such as the <g> or <pc> of an XLIFF <target>.
+type xmlText struct {
+	Text string
+}
+
+func (t *xmlText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	b := &strings.Builder{}
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			b.Write(tok)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				t.Text = b.String()
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// xliffUnit is a <trans-unit> of XLIFF 1.2 or a <unit> of XLIFF 2.0.
+type xliffUnit struct {
+	ID     string   `xml:"id,attr"`
+	Source xmlText  `xml:"source"`
+	Target *xmlText `xml:"target"`
+	// Notes of XLIFF 1.2.
+	Notes []string `xml:"note"`
+	// Notes of XLIFF 2.0.
+	UnitNotes []string `xml:"notes>note"`
+	// Segments of XLIFF 2.0, whose text makes up the unit, along with any
+	// other element.
+	Segments []xliffSegment `xml:",any"`
+}
+
+type xliffSegment struct {
+	XMLName xml.Name
+	Source  xmlText  `xml:"source"`
+	Target  *xmlText `xml:"target"`
+}
+
+// text returns the target of the unit, or its source when it has no target.
+func (u xliffUnit) text() string {
+	b := &strings.Builder{}
+	hasSegments := false
+	for _, segment := range u.Segments {
+		if segment.XMLName.Local == "segment" || segment.XMLName.Local == "ignorable" {
+			hasSegments = true
+			b.WriteString(targetOrSource(segment.Source, segment.Target))
+		}
+	}
+	if !hasSegments {
+		return targetOrSource(u.Source, u.Target)
+	}
+	return b.String()
+}
+
+func targetOrSource(source xmlText, target *xmlText) string {
+	if target != nil && target.Text != "" {
+		return target.Text
+	}
+	return source.Text
+}
+
+// parseXLIFF decodes an XLIFF 1.2 or 2.0 file. Keys are the ids of the
+// <trans-unit> or <unit> elements and values their <target>, or <source>
+// when there is no target. Notes become the descriptions of the keys, and
+// the target language of the file its locale.
+func parseXLIFF(value []byte, l *SourceFile) error {
+	l.Localizations = map[string]string{}
+	l.Descriptions = map[string]string{}
+
+	d := xml.NewDecoder(bytes.NewReader(value))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "xliff", "file":
+			for _, attr := range start.Attr {
+				if attr.Name.Local != "trgLang" && attr.Name.Local != "target-language" {
+					continue
+				}
+				if l.Locale != "" && l.Locale != attr.Value {
+					return fmt.Errorf("line %d: target language %q differs from %q", lineOf(d, value), attr.Value, l.Locale)
+				}
+				l.Locale = attr.Value
+			}
+		case "trans-unit", "unit":
+			line := lineOf(d, value)
+			unit := xliffUnit{}
+			if err := d.DecodeElement(&unit, &start); err != nil {
+				return err
+			}
+			if unit.ID == "" {
+				return fmt.Errorf("line %d: %v without an id", line, start.Name.Local)
+			}
+			if _, ok := l.Localizations[unit.ID]; ok {
+				return fmt.Errorf("line %d: duplicate %v id %q", line, start.Name.Local, unit.ID)
+			}
+			l.Localizations[unit.ID] = unit.text()
+			if notes := append(unit.Notes, unit.UnitNotes...); len(notes) > 0 {
+				l.Descriptions[unit.ID] = strings.Join(notes, "\n")
+			}
+		}
+	}
+	return nil
+}
+
+// lineOf returns the line the decoder has reached in value.
+func lineOf(d *xml.Decoder, value []byte) int {
+	return bytes.Count(value[:d.InputOffset()], []byte("\n")) + 1
+}
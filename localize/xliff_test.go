@@ -0,0 +1,78 @@
+package localize
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func Test_parseXLIFF(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		value   string
+		want    *SourceFile
+		wantErr bool
+	}{
+		{
+			name: "xliff 1.2",
+			file: "mock/xliff/messages.xlf",
+			want: &SourceFile{
+				Locale: "es",
+				Localizations: map[string]string{
+					"hello":           "Hola",
+					"checkout.pay":    "Pagar ahora",
+					"checkout.cancel": "Cancel",
+				},
+				Descriptions: map[string]string{"hello": "Greeting on the home page"},
+			},
+		},
+		{
+			name: "xliff 2.0",
+			file: "mock/xliff/messages.xliff",
+			want: &SourceFile{
+				Locale: "fr",
+				Localizations: map[string]string{
+					"hello":        "Bonjour",
+					"checkout.pay": "Payer maintenant. Thanks!",
+				},
+				Descriptions: map[string]string{"hello": "Greeting on the home page"},
+			},
+		},
+		{
+			name:    "unit without id",
+			value:   `<xliff version="2.0"><file><unit><segment><source>a</source></segment></unit></file></xliff>`,
+			wantErr: true,
+		},
+		{
+			name:    "conflicting target languages",
+			value:   `<xliff version="1.2"><file target-language="es"></file><file target-language="fr"></file></xliff>`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid xml",
+			value:   `<xliff version="1.2"><file>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := []byte(tt.value)
+			if tt.file != "" {
+				var err error
+				if value, err = ioutil.ReadFile(tt.file); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := &SourceFile{}
+			err := parseXLIFF(value, got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseXLIFF() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseXLIFF() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}